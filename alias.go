@@ -0,0 +1,199 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Alias is an alternative key a variable may be set under, for example its name before it
+// was renamed.
+type Alias struct {
+	// Key is the name of the alternative environment variable.
+	Key string
+	// Deprecated reports whether reading the variable through the alias emits a
+	// [*DeprecatedKeyError] warning, see [SetWarningHandler].
+	Deprecated bool
+}
+
+// DeprecatedAlias returns a deprecated [Alias] for the key.
+func DeprecatedAlias(key string) Alias {
+	return Alias{Key: key, Deprecated: true}
+}
+
+var (
+	aliases      sync.Map
+	aliasesInUse atomic.Bool
+)
+
+// RegisterAliases sets the aliases of the variable named by the key, replacing any aliases
+// registered before. When the variable is not present the getters resolve it through the
+// first alias present, in the order the aliases were passed.
+//
+// When the variable and an alias are both present with different values the variable wins
+// and an [*AliasConflictError] warning is emitted. Warnings are emitted once per key and
+// alias, the first time they apply.
+func RegisterAliases(key string, keyAliases ...Alias) {
+	if len(keyAliases) == 0 {
+		aliases.Delete(key)
+		return
+	}
+
+	aliases.Store(key, append([]Alias(nil), keyAliases...))
+	aliasesInUse.Store(true)
+}
+
+func aliasesOf(key string) []Alias {
+	if !aliasesInUse.Load() {
+		return nil
+	}
+
+	if keyAliases, ok := aliases.Load(key); ok {
+		return keyAliases.([]Alias)
+	}
+
+	return nil
+}
+
+// DeprecatedKeyError is emitted as a warning when a variable is resolved through a
+// deprecated alias.
+type DeprecatedKeyError struct {
+	// Key is the deprecated alias.
+	Key string
+	// Replacement is the key the alias was registered for.
+	Replacement string
+}
+
+func (e *DeprecatedKeyError) Error() string {
+	return fmt.Sprintf("env: %s is deprecated, use %s instead", e.Key, e.Replacement)
+}
+
+// AliasConflictError is emitted as a warning, and returned by [CheckAliases], when a
+// variable and one of its aliases are set to different values.
+type AliasConflictError struct {
+	// Key is the key the alias was registered for.
+	Key string
+	// Alias is the alias set to a different value.
+	Alias string
+}
+
+func (e *AliasConflictError) Error() string {
+	return fmt.Sprintf("env: %s and its alias %s are set to different values", e.Key, e.Alias)
+}
+
+var warningHandler atomic.Pointer[func(error)]
+
+// SetWarningHandler sets the function called with warnings about the configuration, such as
+// [*DeprecatedKeyError] and [*AliasConflictError]. Passing nil restores the default handler,
+// which logs warnings with the logger set with [SetLogger], or the default [log/slog.Logger]
+// if there is none.
+func SetWarningHandler(fn func(error)) {
+	if fn == nil {
+		warningHandler.Store(nil)
+		return
+	}
+
+	warningHandler.Store(&fn)
+}
+
+func warn(err error) {
+	if fn := warningHandler.Load(); fn != nil {
+		(*fn)(err)
+		return
+	}
+
+	l := logger.Load()
+	if l == nil {
+		l = slog.Default()
+	}
+
+	l.Warn(err.Error())
+}
+
+// warned holds the messages of the warnings emitted by warnOnce.
+var warned sync.Map
+
+// warnOnce emits the warning unless a warning with the same message was emitted before, so
+// that reading a variable repeatedly does not repeat its warnings.
+func warnOnce(err error) {
+	if _, loaded := warned.LoadOrStore(err.Error(), struct{}{}); !loaded {
+		warn(err)
+	}
+}
+
+// resolveAliases resolves the variable named by the key through its aliases in src. The
+// returned key is the one the value was read from.
+func resolveAliases(src Source, key, val string, ok bool) (string, string, bool) {
+	keyAliases := aliasesOf(key)
+
+	if ok {
+		for _, alias := range keyAliases {
+			recordRead(alias.Key)
+
+			if aliasVal, aliasOk := src.Lookup(alias.Key); aliasOk && aliasVal != val {
+				warnOnce(&AliasConflictError{Key: key, Alias: alias.Key})
+			}
+		}
+
+		return val, key, true
+	}
+
+	for _, alias := range keyAliases {
+		recordRead(alias.Key)
+
+		aliasVal, aliasOk := src.Lookup(alias.Key)
+		if !aliasOk {
+			continue
+		}
+
+		if alias.Deprecated {
+			warnOnce(&DeprecatedKeyError{Key: alias.Key, Replacement: key})
+		}
+
+		return aliasVal, alias.Key, true
+	}
+
+	return val, key, false
+}
+
+// CheckAliases returns an error joining an [*AliasConflictError] for every registered alias
+// set to a different value than its variable in the package [Source], or nil if there are
+// none.
+func CheckAliases() error {
+	src := currentSource()
+
+	var errs []*AliasConflictError
+
+	aliases.Range(func(key, keyAliases any) bool {
+		val, ok := src.Lookup(key.(string))
+		if !ok {
+			return true
+		}
+
+		for _, alias := range keyAliases.([]Alias) {
+			if aliasVal, aliasOk := src.Lookup(alias.Key); aliasOk && aliasVal != val {
+				errs = append(errs, &AliasConflictError{Key: key.(string), Alias: alias.Key})
+			}
+		}
+
+		return true
+	})
+
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Key != errs[j].Key {
+			return errs[i].Key < errs[j].Key
+		}
+
+		return errs[i].Alias < errs[j].Alias
+	})
+
+	joined := make([]error, 0, len(errs))
+	for _, err := range errs {
+		joined = append(joined, err)
+	}
+
+	return errors.Join(joined...)
+}
@@ -0,0 +1,116 @@
+package env
+
+import (
+	"errors"
+	"testing"
+)
+
+func captureWarnings(t *testing.T) *[]error {
+	var warnings []error
+
+	SetWarningHandler(func(err error) {
+		warnings = append(warnings, err)
+	})
+	t.Cleanup(func() {
+		SetWarningHandler(nil)
+
+		warned.Range(func(msg, _ any) bool {
+			warned.Delete(msg)
+			return true
+		})
+	})
+
+	return &warnings
+}
+
+func TestAliases(t *testing.T) {
+	warnings := captureWarnings(t)
+
+	RegisterAliases("KEY_ALIAS_DATABASE_URL", DeprecatedAlias("KEY_ALIAS_DB"), Alias{Key: "KEY_ALIAS_DSN"})
+	t.Cleanup(func() { RegisterAliases("KEY_ALIAS_DATABASE_URL") })
+
+	t.Setenv("KEY_ALIAS_DSN", "dsn")
+
+	if val := GetString("KEY_ALIAS_DATABASE_URL", ""); val != "dsn" {
+		t.Errorf("expected alias value %q got %q", "dsn", val)
+	}
+
+	if len(*warnings) != 0 {
+		t.Errorf("expected no warnings got %v", *warnings)
+	}
+
+	t.Setenv("KEY_ALIAS_DB", "db")
+
+	if val := GetString("KEY_ALIAS_DATABASE_URL", ""); val != "db" {
+		t.Errorf("expected first alias value %q got %q", "db", val)
+	}
+
+	var deprecatedErr *DeprecatedKeyError
+	if len(*warnings) != 1 || !errors.As((*warnings)[0], &deprecatedErr) {
+		t.Fatalf("expected deprecated key warning got %v", *warnings)
+	}
+
+	expected := "env: KEY_ALIAS_DB is deprecated, use KEY_ALIAS_DATABASE_URL instead"
+	if deprecatedErr.Error() != expected {
+		t.Errorf("expected warning %q got %q", expected, deprecatedErr.Error())
+	}
+
+	GetString("KEY_ALIAS_DATABASE_URL", "")
+
+	if len(*warnings) != 1 {
+		t.Errorf("expected the warning once got %v", *warnings)
+	}
+}
+
+func TestAliasesPrimaryUnset(t *testing.T) {
+	warnings := captureWarnings(t)
+
+	RegisterAliases("KEY_ALIAS_NEW", Alias{Key: "KEY_ALIAS_OLD1"}, Alias{Key: "KEY_ALIAS_OLD2"})
+	t.Cleanup(func() { RegisterAliases("KEY_ALIAS_NEW") })
+
+	t.Setenv("KEY_ALIAS_OLD1", "one")
+	t.Setenv("KEY_ALIAS_OLD2", "two")
+
+	if val := GetString("KEY_ALIAS_NEW", ""); val != "one" {
+		t.Errorf("expected first alias value %q got %q", "one", val)
+	}
+
+	if len(*warnings) != 0 {
+		t.Errorf("expected no warnings got %v", *warnings)
+	}
+}
+
+func TestAliasConflict(t *testing.T) {
+	warnings := captureWarnings(t)
+
+	RegisterAliases("KEY_CONFLICT_NEW", DeprecatedAlias("KEY_CONFLICT_OLD"))
+	t.Cleanup(func() { RegisterAliases("KEY_CONFLICT_NEW") })
+
+	t.Setenv("KEY_CONFLICT_NEW", "new")
+	t.Setenv("KEY_CONFLICT_OLD", "new")
+
+	if err := CheckAliases(); err != nil {
+		t.Errorf("expected no conflict got %s", err.Error())
+	}
+
+	t.Setenv("KEY_CONFLICT_OLD", "old")
+
+	if val := GetString("KEY_CONFLICT_NEW", ""); val != "new" {
+		t.Errorf("expected variable value %q got %q", "new", val)
+	}
+
+	var conflictErr *AliasConflictError
+	if len(*warnings) != 1 || !errors.As((*warnings)[0], &conflictErr) {
+		t.Fatalf("expected alias conflict warning got %v", *warnings)
+	}
+
+	err := CheckAliases()
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected alias conflict error got %v", err)
+	}
+
+	expected := "env: KEY_CONFLICT_NEW and its alias KEY_CONFLICT_OLD are set to different values"
+	if conflictErr.Error() != expected {
+		t.Errorf("expected error %q got %q", expected, conflictErr.Error())
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rojbar/env/v2"
+)
+
+// result is the outcome of checking a variable.
+type result struct {
+	key      string
+	set      bool
+	problems []string
+}
+
+func (r result) String() string {
+	switch {
+	case len(r.problems) > 0:
+		return fmt.Sprintf("%s: %s", r.key, strings.Join(r.problems, "; "))
+	case r.set:
+		return r.key + ": ok"
+	default:
+		return r.key + ": ok (not set)"
+	}
+}
+
+// check validates the variables of src against the schema. Values are parsed by binding them
+// with env.UnmarshalFrom to a struct holding a field of the type of every entry, tagged with
+// its range and allowed values, so they are parsed and checked exactly like env.Unmarshal
+// parses and checks them. If prefix is not empty, variables of src with
+// the prefix that are not in the schema are reported as unknown.
+func check(src env.Source, schema []*entry, prefix string) []result {
+	fields := make([]reflect.StructField, 0, len(schema))
+	for i, e := range schema {
+		key := e.key
+		if e.secret {
+			key += ",secret"
+		}
+
+		tag := fmt.Sprintf(`env:%q envSeparator:%q envBase:"%d"`, key, e.separator, e.base)
+		if e.slice != "" {
+			tag += fmt.Sprintf(" envSlice:%q", e.slice)
+		}
+
+		if e.min != "" {
+			tag += fmt.Sprintf(" envMin:%q", e.min)
+		}
+
+		if e.max != "" {
+			tag += fmt.Sprintf(" envMax:%q", e.max)
+		}
+
+		if len(e.enum) > 0 {
+			tag += fmt.Sprintf(" envEnum:%q", strings.Join(e.enum, ","))
+		}
+
+		fields = append(fields, reflect.StructField{Name: "F" + strconv.Itoa(i), Type: e.typ, Tag: reflect.StructTag(tag)})
+	}
+
+	cfg := reflect.New(reflect.StructOf(fields)).Elem()
+
+	problems := make(map[string][]string)
+	for _, err := range unwrap(env.UnmarshalFrom(src, cfg.Addr().Interface())) {
+		var parseErr *env.ParseError
+		if errors.As(err, &parseErr) {
+			problems[parseErr.Key] = append(problems[parseErr.Key], describeParseError(parseErr, schema))
+		}
+	}
+
+	results := make([]result, 0, len(schema))
+
+	for _, e := range schema {
+		_, set := src.Lookup(e.key)
+
+		r := result{key: e.key, set: set, problems: problems[e.key]}
+		if !set && e.required {
+			r.problems = append(r.problems, "required variable is not set")
+		}
+
+		results = append(results, r)
+	}
+
+	known := make(map[string]struct{}, len(schema))
+	for _, e := range schema {
+		known[e.key] = struct{}{}
+	}
+
+	return append(results, unknown(src, known, prefix)...)
+}
+
+// checkJSONSchema validates the variables of src against a JSON Schema with
+// env.JSONSchema.Validate, reporting the variables in lexicographical order of their keys.
+func checkJSONSchema(src env.Source, schema *env.JSONSchema, prefix string) []result {
+	values := make(map[string]string)
+	for _, key := range src.Keys() {
+		if val, ok := src.Lookup(key); ok {
+			values[key] = val
+		}
+	}
+
+	problems := make(map[string][]string)
+	for _, err := range unwrap(schema.Validate(values)) {
+		var (
+			parseErr    *env.ParseError
+			requiredErr *env.RequiredError
+		)
+
+		switch {
+		case errors.As(err, &parseErr) && schema.Properties[parseErr.Key].WriteOnly:
+			problems[parseErr.Key] = append(problems[parseErr.Key], "invalid value")
+		case errors.As(err, &parseErr):
+			problems[parseErr.Key] = append(problems[parseErr.Key], fmt.Sprintf("invalid value %q: %s", parseErr.Value, parseErr.Err.Error()))
+		case errors.As(err, &requiredErr):
+			problems[requiredErr.Key] = append(problems[requiredErr.Key], "required variable is not set")
+		}
+	}
+
+	known := make(map[string]struct{}, len(schema.Properties))
+	keys := make([]string, 0, len(schema.Properties))
+
+	for key := range schema.Properties {
+		known[key] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	results := make([]result, 0, len(keys))
+	for _, key := range keys {
+		_, set := values[key]
+		results = append(results, result{key: key, set: set, problems: problems[key]})
+	}
+
+	return append(results, unknown(src, known, prefix)...)
+}
+
+// unknown reports the variables of src with the prefix that are not known, unless the prefix
+// is empty.
+func unknown(src env.Source, known map[string]struct{}, prefix string) []result {
+	if prefix == "" {
+		return nil
+	}
+
+	keys := src.Keys()
+	sort.Strings(keys)
+
+	var results []result
+
+	for _, key := range keys {
+		if _, ok := known[key]; !ok && strings.HasPrefix(key, prefix) {
+			results = append(results, result{key: key, set: true, problems: []string{"unknown variable"}})
+		}
+	}
+
+	return results
+}
+
+func unwrap(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+
+	if err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
+
+func describeParseError(err *env.ParseError, schema []*entry) string {
+	for _, e := range schema {
+		if e.key == err.Key && e.secret {
+			return "invalid " + e.typeName + " value"
+		}
+	}
+
+	return fmt.Sprintf("invalid value %q: %s", err.Value, err.Err.Error())
+}
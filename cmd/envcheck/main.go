@@ -0,0 +1,154 @@
+// Envcheck validates an environment against a schema before it is used to start a service.
+//
+// The schema lists one variable per line: the key, the type and the options of the variable
+// separated by spaces. Empty lines and lines starting with # are ignored.
+//
+//	# HTTP server
+//	PORT      uint16      required min=1 max=65535
+//	LOG_LEVEL string      enum=debug,info,warn,error
+//	TIMEOUT   duration    min=100ms max=1m
+//	HOSTS     []string    separator=" " slice=dropempty
+//	MASK      uint32      base=16
+//	DB_URL    url         required
+//	PASSWORD  string      required secret
+//
+// The types are string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32,
+// uint64, float32, float64, duration and url, or a slice of them written as []type. Values
+// are parsed with the same rules as the getters of env. The options are:
+//
+//	required       the variable must be set
+//	secret         the value is never printed
+//	separator=s    the separator of the elements of a slice, "," by default
+//	slice=a,b      how a slice is split, as in the envSlice tag of env.Unmarshal: trimspace,
+//	               dropempty, quoted, escaped and skipinvalid
+//	base=n         the base of integers, 10 by default, or 0 to infer it from the prefix
+//	min=v, max=v   the range of numbers and durations, inclusive
+//	enum=a,b,c     the allowed values
+//
+// Option values containing spaces are written as Go double quoted strings, e.g. separator=" ".
+//
+// A schema file whose name ends with .json is read as a JSON Schema instead, as written by
+// env.SchemaOf or env.RegistrySchema, and the variables are validated with
+// env.JSONSchema.Validate.
+//
+// Usage:
+//
+//	envcheck -schema file [-dotenv file] [-prefix prefix] [-quiet]
+//
+// Envcheck validates the process environment, or the dotenv file given with -dotenv, prints
+// the result for every variable of the schema and exits with status 1 if any of them is
+// invalid. If -prefix is given, variables with the prefix that are not in the schema are
+// reported as unknown. The exit status is 2 if the schema can not be read.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/rojbar/env/v2"
+)
+
+// errInvalid is returned by run when the environment does not match the schema.
+var errInvalid = errors.New("invalid environment")
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, errInvalid):
+		os.Exit(1)
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "envcheck: %s\n", err.Error())
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("envcheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	schemaPath := fs.String("schema", "", "read the schema from the `file`")
+	dotenvPath := fs.String("dotenv", "", "validate the dotenv `file` instead of the environment")
+	prefix := fs.String("prefix", "", "report variables with the `prefix` that are not in the schema")
+	quiet := fs.Bool("quiet", false, "only print invalid variables")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *schemaPath == "" {
+		fs.Usage()
+		return errors.New("-schema is required")
+	}
+
+	src := env.OS()
+	if *dotenvPath != "" {
+		d, err := env.LoadDotenv(*dotenvPath)
+		if err != nil {
+			return err
+		}
+
+		src = d
+	}
+
+	results, err := checkFile(src, *schemaPath, *prefix)
+	if err != nil {
+		return err
+	}
+
+	invalid := false
+
+	for _, r := range results {
+		if len(r.problems) > 0 {
+			invalid = true
+		} else if *quiet {
+			continue
+		}
+
+		fmt.Fprintln(stdout, r.String())
+	}
+
+	if invalid {
+		return errInvalid
+	}
+
+	return nil
+}
+
+// checkFile validates src against the schema in the file named by the path, a JSON Schema if
+// its name ends with .json.
+func checkFile(src env.Source, path, prefix string) ([]result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if filepath.Ext(path) == ".json" {
+		var schema env.JSONSchema
+
+		decoder := json.NewDecoder(f)
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&schema); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		return checkJSONSchema(src, &schema, prefix), nil
+	}
+
+	schema, err := parseSchema(f, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return check(src, schema, prefix), nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunValid(t *testing.T) {
+	var stdout bytes.Buffer
+
+	err := run([]string{"-schema", "testdata/app.schema", "-dotenv", "testdata/valid.env", "-prefix", "APP_"}, &stdout, io.Discard)
+	if err != nil {
+		t.Errorf("expected valid environment got %s\n%s", err.Error(), stdout.String())
+	}
+
+	expected := "APP_PORT: ok\n" +
+		"APP_LOG_LEVEL: ok\n" +
+		"APP_TIMEOUT: ok\n" +
+		"APP_HOSTS: ok\n" +
+		"APP_RETRIES: ok\n" +
+		"APP_MASK: ok\n" +
+		"APP_DB_URL: ok\n" +
+		"APP_PASSWORD: ok\n" +
+		"APP_DEBUG: ok (not set)\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q got %q", expected, stdout.String())
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	var stdout bytes.Buffer
+
+	err := run([]string{"-schema", "testdata/app.schema", "-dotenv", "testdata/invalid.env", "-prefix", "APP_", "-quiet"}, &stdout, io.Discard)
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("expected invalid environment got %v", err)
+	}
+
+	expected := "APP_PORT: invalid value \"0\": 0 is less than the minimum 1\n" +
+		"APP_LOG_LEVEL: invalid value \"trace\": \"trace\" is not one of \"debug\", \"info\", \"warn\", \"error\"\n" +
+		"APP_TIMEOUT: invalid value \"soon\": time: invalid duration \"soon\"\n" +
+		"APP_RETRIES: invalid value \"1,7\": element 1: 7 is greater than the maximum 5\n" +
+		"APP_MASK: invalid value \"zz\": strconv.ParseUint: parsing \"zz\": invalid syntax\n" +
+		"APP_DB_URL: required variable is not set\n" +
+		"APP_PASSWORD: invalid string value\n" +
+		"APP_DEBUGG: unknown variable\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q got %q", expected, stdout.String())
+	}
+
+	if strings.Contains(stdout.String(), "hunter2") {
+		t.Errorf("expected secret value to be redacted got %q", stdout.String())
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected string
+	}{
+		{"PORT", "test.schema:1: expected a key and a type"},
+		{"PORT int16 max=70000", "test.schema:1: invalid max: strconv.ParseInt: parsing \"70000\": value out of range"},
+		{"PORT complex128", "test.schema:1: unknown type \"complex128\""},
+		{"NAME string min=1", "test.schema:1: min and max are not supported for string"},
+		{"NAME string optional", "test.schema:1: invalid option \"optional\""},
+		{"NAME []string separator=\" ", "test.schema:1: unterminated quoted value"},
+		{"NAME []string slice=trim", "test.schema:1: unknown slice option \"trim\""},
+		{"NAME string\n\nNAME int", "test.schema:3: NAME already declared on line 1"},
+	}
+
+	for _, test := range tests {
+		_, err := parseSchema(strings.NewReader(test.schema), "test.schema")
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("expected error %q got %v", test.expected, err)
+		}
+	}
+}
+
+func TestRunJSONSchema(t *testing.T) {
+	var stdout bytes.Buffer
+
+	err := run([]string{"-schema", "testdata/app.schema.json", "-dotenv", "testdata/valid.env"}, &stdout, io.Discard)
+	if err != nil {
+		t.Errorf("expected valid environment got %s\n%s", err.Error(), stdout.String())
+	}
+
+	stdout.Reset()
+
+	err = run([]string{"-schema", "testdata/app.schema.json", "-dotenv", "testdata/invalid.env", "-prefix", "APP_", "-quiet"}, &stdout, io.Discard)
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("expected invalid environment got %v", err)
+	}
+
+	expected := "APP_DB_URL: required variable is not set\n" +
+		"APP_LOG_LEVEL: invalid value \"trace\": \"trace\" is not one of \"debug\", \"info\", \"warn\", \"error\"\n" +
+		"APP_MASK: invalid value \"zz\": \"zz\" is not an integer\n" +
+		"APP_PASSWORD: invalid value\n" +
+		"APP_PORT: invalid value \"0\": 0 is less than the minimum 1\n" +
+		"APP_TIMEOUT: invalid value \"soon\": time: invalid duration \"soon\"\n" +
+		"APP_DEBUGG: unknown variable\n" +
+		"APP_RETRIES: unknown variable\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q got %q", expected, stdout.String())
+	}
+}
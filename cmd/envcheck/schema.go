@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rojbar/env/v2"
+)
+
+// entry describes a variable of a schema.
+type entry struct {
+	key       string
+	typeName  string
+	typ       reflect.Type
+	required  bool
+	secret    bool
+	separator string
+	slice     string
+	base      int
+	min       string
+	max       string
+	enum      []string
+	line      int
+}
+
+var scalarTypes = map[string]reflect.Type{
+	"string":   reflect.TypeOf(""),
+	"bool":     reflect.TypeOf(false),
+	"int":      reflect.TypeOf(0),
+	"int8":     reflect.TypeOf(int8(0)),
+	"int16":    reflect.TypeOf(int16(0)),
+	"int32":    reflect.TypeOf(int32(0)),
+	"int64":    reflect.TypeOf(int64(0)),
+	"uint":     reflect.TypeOf(uint(0)),
+	"uint8":    reflect.TypeOf(uint8(0)),
+	"uint16":   reflect.TypeOf(uint16(0)),
+	"uint32":   reflect.TypeOf(uint32(0)),
+	"uint64":   reflect.TypeOf(uint64(0)),
+	"float32":  reflect.TypeOf(float32(0)),
+	"float64":  reflect.TypeOf(float64(0)),
+	"duration": reflect.TypeOf(time.Duration(0)),
+	"url":      reflect.TypeOf(url.URL{}),
+}
+
+// sliceOptions are the names of the options of the envSlice tag of env.Unmarshal.
+var sliceOptions = map[string]struct{}{
+	"trimspace":   {},
+	"dropempty":   {},
+	"quoted":      {},
+	"escaped":     {},
+	"skipinvalid": {},
+}
+
+// elem returns the type of the value of the entry, or of its elements if it is a slice.
+func (e *entry) elem() reflect.Type {
+	if e.typ.Kind() == reflect.Slice {
+		return e.typ.Elem()
+	}
+
+	return e.typ
+}
+
+// parseSchema reads a schema with one variable per line: the key, the type and the options
+// of the variable separated by spaces, e.g.
+//
+//	PORT      uint16    required min=1 max=65535
+//	LOG_LEVEL string    enum=debug,info,warn,error
+//	HOSTS     []string  separator=" "
+//
+// Empty lines and lines starting with # are ignored.
+func parseSchema(r io.Reader, name string) ([]*entry, error) {
+	var entries []*entry
+
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields, err := splitFields(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+
+		e, err := parseEntry(fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+
+		if prev, ok := seen[e.key]; ok {
+			return nil, fmt.Errorf("%s:%d: %s already declared on line %d", name, line, e.key, prev)
+		}
+
+		e.line = line
+		seen[e.key] = line
+		entries = append(entries, e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// splitFields splits a line at spaces outside of double quoted option values.
+func splitFields(line string) ([]string, error) {
+	var (
+		fields  []string
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t'):
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quoted value")
+	}
+
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields, nil
+}
+
+func parseEntry(fields []string) (*entry, error) {
+	if len(fields) < 2 {
+		return nil, errors.New("expected a key and a type")
+	}
+
+	e := &entry{key: fields[0], typeName: fields[1], separator: ",", base: 10}
+
+	elemName, slice := strings.CutPrefix(e.typeName, "[]")
+
+	elem, ok := scalarTypes[elemName]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", e.typeName)
+	}
+
+	e.typ = elem
+	if slice {
+		e.typ = reflect.SliceOf(elem)
+	}
+
+	for _, opt := range fields[2:] {
+		name, val, hasValue := strings.Cut(opt, "=")
+
+		if hasValue && strings.HasPrefix(val, `"`) {
+			unquoted, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value %s", val)
+			}
+
+			val = unquoted
+		}
+
+		switch {
+		case name == "required" && !hasValue:
+			e.required = true
+		case name == "secret" && !hasValue:
+			e.secret = true
+		case name == "separator" && hasValue && val != "":
+			e.separator = val
+		case name == "slice" && hasValue:
+			for _, opt := range strings.Split(val, ",") {
+				if _, ok := sliceOptions[opt]; !ok {
+					return nil, fmt.Errorf("unknown slice option %q", opt)
+				}
+			}
+
+			e.slice = val
+		case name == "base" && hasValue:
+			base, err := strconv.Atoi(val)
+			if err != nil || base == 1 || base < 0 || base > 36 {
+				return nil, fmt.Errorf("invalid base %q", val)
+			}
+
+			e.base = base
+		case name == "min" && hasValue:
+			e.min = val
+		case name == "max" && hasValue:
+			e.max = val
+		case name == "enum" && hasValue:
+			e.enum = strings.Split(val, ",")
+		default:
+			return nil, fmt.Errorf("invalid option %q", opt)
+		}
+	}
+
+	if (e.min != "" || e.max != "") && !ordered(elem) {
+		return nil, fmt.Errorf("min and max are not supported for %s", elemName)
+	}
+
+	if err := e.parseBound(e.min); err != nil {
+		return nil, fmt.Errorf("invalid min: %w", err)
+	}
+
+	if err := e.parseBound(e.max); err != nil {
+		return nil, fmt.Errorf("invalid max: %w", err)
+	}
+
+	for _, s := range e.enum {
+		if _, err := e.parse(s); err != nil {
+			return nil, fmt.Errorf("invalid enum value: %w", err)
+		}
+	}
+
+	return e, nil
+}
+
+func ordered(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *entry) parseBound(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	_, err := e.parse(s)
+
+	return err
+}
+
+// parse parses s into a value of the element type of the entry with the rules of the getter
+// for the type.
+func (e *entry) parse(s string) (reflect.Value, error) {
+	tag := fmt.Sprintf(`env:"V" envBase:"%d"`, e.base)
+	cfg := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "V", Type: e.elem(), Tag: reflect.StructTag(tag)}}))
+
+	if err := env.UnmarshalFrom(env.NewMap(map[string]string{"V": s}), cfg.Interface()); err != nil {
+		var parseErr *env.ParseError
+		if errors.As(err, &parseErr) {
+			return reflect.Value{}, parseErr.Err
+		}
+
+		return reflect.Value{}, err
+	}
+
+	return cfg.Elem().Field(0), nil
+}
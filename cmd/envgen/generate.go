@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rojbar/env/v2"
+)
+
+// generate returns the source of a file of the package declaring funcName, which reads the
+// struct type named by typeName with the getters of env, and funcName+"From".
+func generate(pkgName, typeName, funcName string, args []string, fields []field) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by envgen %s; DO NOT EDIT.\n\n", strings.Join(args, " "))
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	b.WriteString("import \"github.com/rojbar/env/v2\"\n\n")
+
+	defaults := "nil"
+
+	var withDefaults []field
+	for _, f := range fields {
+		if f.hasDefault {
+			withDefaults = append(withDefaults, f)
+		}
+	}
+
+	if len(withDefaults) > 0 {
+		defaults = lowerFirst(typeName) + "Defaults"
+
+		fmt.Fprintf(&b, "// %s holds the default values of the variables read into a %s.\n", defaults, typeName)
+		fmt.Fprintf(&b, "var %s = map[string]string{\n", defaults)
+
+		for _, f := range withDefaults {
+			fmt.Fprintf(&b, "%q: %q,\n", f.key, f.defaultValue)
+		}
+
+		b.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(&b, "// %s reads a %s from the package source of env, the process environment unless\n", funcName, typeName)
+	b.WriteString("// replaced with env.SetSource.\n")
+	fmt.Fprintf(&b, "func %s() (%s, error) {\n", funcName, typeName)
+	fmt.Fprintf(&b, "return %sFrom(nil)\n", funcName)
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// %sFrom reads a %s from src. Every field is read even if another one fails, and the\n", funcName, typeName)
+	b.WriteString("// returned error joins an *env.RequiredError for every missing required variable and an\n")
+	b.WriteString("// *env.ParseError for every value that could not be parsed.\n")
+	fmt.Fprintf(&b, "func %sFrom(src env.Source) (%s, error) {\n", funcName, typeName)
+	fmt.Fprintf(&b, "var cfg %s\n\n", typeName)
+	fmt.Fprintf(&b, "l := env.NewLoader(src, %s)\n", defaults)
+
+	for _, f := range fields {
+		if f.secret {
+			fmt.Fprintf(&b, "l.Secret(%q)\n", f.key)
+		}
+	}
+
+	for _, f := range fields {
+		if f.required {
+			fmt.Fprintf(&b, "l.Require(%q)\n", f.key)
+		}
+	}
+
+	b.WriteString("\n")
+
+	for _, f := range fields {
+		fmt.Fprintf(&b, "cfg.%s = %s\n", f.path, f.getterCall())
+	}
+
+	b.WriteString("\nreturn cfg, l.Err()\n")
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// sliceOptions maps the names of the envSlice tag to the functions returning the options.
+var sliceOptions = map[string]string{
+	"trimspace":   "SliceTrimSpace",
+	"dropempty":   "SliceDropEmpty",
+	"quoted":      "SliceQuoted",
+	"escaped":     "SliceEscaped",
+	"skipinvalid": "SliceSkipInvalid",
+}
+
+// getterCall returns the call of the getter reading the field through the loader l.
+func (f field) getterCall() string {
+	args := []string{"l", strconv.Quote(f.key)}
+
+	name := "Get" + f.kind.getter
+	if f.slice {
+		name += "Slice"
+		args = append(args, strconv.Quote(f.separator))
+	}
+
+	if f.kind.integer() {
+		args = append(args, strconv.Itoa(f.base))
+	}
+
+	args = append(args, "cfg."+f.path)
+
+	for _, name := range f.sliceOptions {
+		args = append(args, "env."+sliceOptions[name]+"()")
+	}
+
+	if f.indexed != nil {
+		args = append(args, fmt.Sprintf("env.SliceIndexed(%q, %d)", f.indexed.separator, f.indexed.start))
+	}
+
+	return fmt.Sprintf("env.%sFrom(%s)", name, strings.Join(args, ", "))
+}
+
+func (f field) typeName() string {
+	if f.slice {
+		return "[]" + f.kind.name
+	}
+
+	return f.kind.name
+}
+
+// exampleKey returns the key of the variable of the field, or of the first element of an
+// indexed slice.
+func (f field) exampleKey() string {
+	if f.indexed == nil {
+		return f.key
+	}
+
+	return f.key + f.indexed.separator + strconv.Itoa(f.indexed.start)
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+// writeMarkdown writes a Markdown table describing the variables of the fields to w, in the
+// format of env.WriteMarkdown without the current values.
+func writeMarkdown(w io.Writer, fields []field) error {
+	var b strings.Builder
+
+	b.WriteString("| Key | Type | Default | Required | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, f := range fields {
+		required := "no"
+		if f.required {
+			required = "yes"
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n",
+			f.key,
+			markdownCell(f.typeName()),
+			markdownCode(f.defaultValue),
+			required,
+			markdownCell(f.usage),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + markdownCell(s) + "`"
+}
+
+// writeDotenvExample writes a .env.example file listing the variables of the fields with
+// their default values to w, in the format of env.WriteDotenvExample.
+func writeDotenvExample(w io.Writer, fields []field) error {
+	var b strings.Builder
+
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		for _, line := range strings.Split(f.usage, "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "# %s\n", line)
+			}
+		}
+
+		fmt.Fprintf(&b, "# type: %s", f.typeName())
+		if f.required {
+			b.WriteString(", required")
+		}
+
+		b.WriteString("\n")
+
+		if err := env.WriteDotenv(&b, []string{f.exampleKey() + "=" + f.defaultValue}); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
@@ -0,0 +1,47 @@
+// Package example holds a configuration struct with a loader generated by envgen.
+package example
+
+import (
+	"net/url"
+	"time"
+)
+
+//go:generate go run github.com/rojbar/env/v2/cmd/envgen -type Config -doc ENV.md -example .env.example
+
+// Level is a logging level.
+type Level string
+
+// Config is the configuration of an example service.
+type Config struct {
+	// Name of the service.
+	Name string `env:"NAME" envDefault:"example"`
+	// Level is the minimum level of logged messages.
+	Level Level `env:"LOG_LEVEL" envDefault:"info"`
+	// Port the service listens on.
+	Port uint16 `env:"PORT,required"`
+	// Mode is the permission bits of created files.
+	Mode uint32 `env:"FILE_MODE" envBase:"8" envDefault:"644"`
+	// Timeout of outgoing requests.
+	Timeout time.Duration `env:"TIMEOUT" envDefault:"5s"`
+	// Backoffs between retries of failed requests.
+	Backoffs []time.Duration `env:"BACKOFFS" envSeparator:" " envDefault:"100ms 1s"`
+	// Peers are the other instances of the service.
+	Peers []url.URL `env:"PEERS" envSlice:"trimspace,dropempty"`
+
+	// Tags are attached to the metrics of the service.
+	Tags []string `env:"TAG" envIndexed:"_" envIndexStart:"1"`
+
+	Database Database `envPrefix:"DB_"`
+
+	started time.Time
+}
+
+// Database is the configuration of the database of an example service.
+type Database struct {
+	// URL of the database.
+	URL url.URL `env:"URL,required"`
+	// Password of the database user.
+	Password string `env:"PASSWORD,secret"`
+	// Ratio of connections kept open when idle.
+	IdleRatio float64 `env:"IDLE_RATIO" envDefault:"0.5"`
+}
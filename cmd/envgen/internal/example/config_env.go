@@ -0,0 +1,47 @@
+// Code generated by envgen -type Config -doc ENV.md -example .env.example; DO NOT EDIT.
+
+package example
+
+import "github.com/rojbar/env/v2"
+
+// configDefaults holds the default values of the variables read into a Config.
+var configDefaults = map[string]string{
+	"NAME":          "example",
+	"LOG_LEVEL":     "info",
+	"FILE_MODE":     "644",
+	"TIMEOUT":       "5s",
+	"BACKOFFS":      "100ms 1s",
+	"DB_IDLE_RATIO": "0.5",
+}
+
+// Load reads a Config from the package source of env, the process environment unless
+// replaced with env.SetSource.
+func Load() (Config, error) {
+	return LoadFrom(nil)
+}
+
+// LoadFrom reads a Config from src. Every field is read even if another one fails, and the
+// returned error joins an *env.RequiredError for every missing required variable and an
+// *env.ParseError for every value that could not be parsed.
+func LoadFrom(src env.Source) (Config, error) {
+	var cfg Config
+
+	l := env.NewLoader(src, configDefaults)
+	l.Secret("DB_PASSWORD")
+	l.Require("PORT")
+	l.Require("DB_URL")
+
+	cfg.Name = env.GetStringFrom(l, "NAME", cfg.Name)
+	cfg.Level = env.GetStringFrom(l, "LOG_LEVEL", cfg.Level)
+	cfg.Port = env.GetUintFrom(l, "PORT", 10, cfg.Port)
+	cfg.Mode = env.GetUintFrom(l, "FILE_MODE", 8, cfg.Mode)
+	cfg.Timeout = env.GetDurationFrom(l, "TIMEOUT", cfg.Timeout)
+	cfg.Backoffs = env.GetDurationSliceFrom(l, "BACKOFFS", " ", cfg.Backoffs)
+	cfg.Peers = env.GetURLSliceFrom(l, "PEERS", ",", cfg.Peers, env.SliceTrimSpace(), env.SliceDropEmpty())
+	cfg.Tags = env.GetStringSliceFrom(l, "TAG", ",", cfg.Tags, env.SliceIndexed("_", 1))
+	cfg.Database.URL = env.GetURLFrom(l, "DB_URL", cfg.Database.URL)
+	cfg.Database.Password = env.GetStringFrom(l, "DB_PASSWORD", cfg.Database.Password)
+	cfg.Database.IdleRatio = env.GetFloatFrom(l, "DB_IDLE_RATIO", cfg.Database.IdleRatio)
+
+	return cfg, l.Err()
+}
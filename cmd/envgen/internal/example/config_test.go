@@ -0,0 +1,60 @@
+package example
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rojbar/env/v2"
+)
+
+func TestLoadFrom(t *testing.T) {
+	cfg, err := LoadFrom(env.NewMap(map[string]string{
+		"PORT":        "8080",
+		"LOG_LEVEL":   "debug",
+		"PEERS":       "http://a:8080, http://b:8080,",
+		"TAG_1":       "blue",
+		"TAG_2":       "eu,west",
+		"DB_URL":      "postgres://localhost/app",
+		"DB_PASSWORD": "hunter2",
+	}))
+	if err != nil {
+		t.Fatalf("load failed %s", err.Error())
+	}
+
+	if cfg.Name != "example" || cfg.Level != "debug" || cfg.Port != 8080 || cfg.Mode != 0o644 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	if cfg.Timeout != 5*time.Second || len(cfg.Backoffs) != 2 || cfg.Backoffs[1] != time.Second {
+		t.Errorf("expected default durations got %s %v", cfg.Timeout, cfg.Backoffs)
+	}
+
+	if len(cfg.Peers) != 2 || cfg.Peers[1].Host != "b:8080" {
+		t.Errorf("expected peers got %v", cfg.Peers)
+	}
+
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "blue" || cfg.Tags[1] != "eu,west" {
+		t.Errorf("expected tags got %v", cfg.Tags)
+	}
+
+	if cfg.Database.URL.Path != "/app" || cfg.Database.Password != "hunter2" || cfg.Database.IdleRatio != 0.5 {
+		t.Errorf("unexpected database config %+v", cfg.Database)
+	}
+}
+
+func TestLoadFromErrors(t *testing.T) {
+	_, err := LoadFrom(env.NewMap(map[string]string{"PORT": "65536", "TIMEOUT": "soon"}))
+
+	var requiredErr *env.RequiredError
+	if !errors.As(err, &requiredErr) || requiredErr.Key != "DB_URL" {
+		t.Errorf("expected required error for DB_URL got %v", err)
+	}
+
+	expected := "env: invalid value for PORT: strconv.ParseUint: parsing \"65536\": value out of range\n" +
+		"env: invalid value for TIMEOUT: time: invalid duration \"soon\"\n" +
+		"env: required variable DB_URL is not set"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q got %v", expected, err)
+	}
+}
@@ -0,0 +1,114 @@
+// Envgen generates reflection-free loaders for configuration structs bound to environment
+// variables with the tags understood by env.Unmarshal.
+//
+// Given a struct type in the package of the current directory, envgen writes a Go file
+// declaring a function reading the struct with the getters of env, so that unsupported field
+// types and invalid defaults are reported when the code is generated rather than when it
+// runs. The envMin, envMax and envEnum tags, slices and maps of structs, map and Optional
+// fields and defaults of indexed slices are not supported. It is meant to be run by go
+// generate:
+//
+//	//go:generate go run github.com/rojbar/env/v2/cmd/envgen -type Config -doc ENV.md -example .env.example
+//
+// The flags are:
+//
+//	-type name
+//		the struct type to generate a loader for (required)
+//	-func name
+//		the name of the generated function, Load by default; a function of the same name
+//		suffixed with From reads the struct from an env.Source
+//	-output file
+//		the generated Go file, the lower case type name suffixed with _env.go by default
+//	-doc file
+//		also write a Markdown table documenting the variables to the file
+//	-example file
+//		also write a dotenv file listing the variables with their defaults to the file
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], ".", os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "envgen: %s\n", err.Error())
+		}
+
+		os.Exit(2)
+	}
+}
+
+// run generates the files requested by args for the package in dir.
+func run(args []string, dir string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("envgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	typeName := fs.String("type", "", "the struct `type` to generate a loader for")
+	funcName := fs.String("func", "Load", "the `name` of the generated function")
+	output := fs.String("output", "", "the generated Go `file`")
+	doc := fs.String("doc", "", "write a Markdown table documenting the variables to the `file`")
+	example := fs.String("example", "", "write a dotenv file listing the variables to the `file`")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *typeName == "" {
+		fs.Usage()
+		return errors.New("-type is required")
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_env.go"
+	}
+
+	p, err := parsePackage(dir, filepath.Join(dir, *output))
+	if err != nil {
+		return err
+	}
+
+	fields, err := p.fields(*typeName)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(p.name, *typeName, *funcName, args, fields)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		return err
+	}
+
+	if *doc != "" {
+		if err := writeFile(filepath.Join(dir, *doc), fields, writeMarkdown); err != nil {
+			return err
+		}
+	}
+
+	if *example != "" {
+		if err := writeFile(filepath.Join(dir, *example), fields, writeDotenvExample); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeFile(path string, fields []field, write func(io.Writer, []field) error) error {
+	var b bytes.Buffer
+	if err := write(&b, fields); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateExample(t *testing.T) {
+	dir := t.TempDir()
+
+	src, err := os.ReadFile("internal/example/config.go")
+	if err != nil {
+		t.Fatalf("read example failed %s", err.Error())
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), src, 0o644); err != nil {
+		t.Fatalf("write example failed %s", err.Error())
+	}
+
+	if err := run([]string{"-type", "Config", "-doc", "ENV.md", "-example", ".env.example"}, dir, io.Discard); err != nil {
+		t.Fatalf("generate failed %s", err.Error())
+	}
+
+	for _, name := range []string{"config_env.go", "ENV.md", ".env.example"} {
+		expected, err := os.ReadFile(filepath.Join("internal/example", name))
+		if err != nil {
+			t.Fatalf("read %s failed %s", name, err.Error())
+		}
+
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read generated %s failed %s", name, err.Error())
+		}
+
+		if string(got) != string(expected) {
+			t.Errorf("expected generated %s to match internal/example, run go generate ./...\ngot:\n%s", name, got)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"type Config struct {\n\tValues map[string]string `env:\"VALUES\"`\n}", "field Values has unsupported type map[string]string"},
+		{"type Config struct {\n\tPort *int `env:\"PORT\"`\n}", "field Port has unsupported type *int"},
+		{"type Config struct {\n\tPort int `env:\"PORT\" envDefault:\"http\"`\n}", "field Port: invalid default"},
+		{"type Config struct {\n\tPort int8 `env:\"PORT\" envDefault:\"300\"`\n}", "field Port: invalid default"},
+		{"type Config struct {\n\tPort int `env:\"PORT,optional\"`\n}", "field Port has unknown option \"optional\""},
+		{"type Config struct {\n\tPort int `env:\"PORT\" envBase:\"1\"`\n}", "field Port has invalid base \"1\""},
+		{"type Config struct {\n\tHosts []string `env:\"HOSTS\" envSlice:\"trim\"`\n}", "field Hosts has unknown slice option \"trim\""},
+		{"type Config struct {\n\tPort int `env:\"PORT\" envMax:\"10\"`\n}", "field Port: the envMax tag is not supported"},
+		{"type Config struct {\n\tTags []string `env:\"TAG\" envIndexed:\"_\" envDefault:\"a\"`\n}", "field Tags: defaults of indexed slices are not supported"},
+		{"type Config struct {\n\tPeers []struct{ URL string `env:\"URL\"` } `envPrefix:\"PEER_\"`\n}", "field Peers: slices of structs are not supported"},
+		{"type Config struct {\n\tCaches map[string]struct{ TTL string `env:\"TTL\"` } `envPrefix:\"CACHE_\"`\n}", "field Caches: maps of structs are not supported"},
+		{"import \"github.com/rojbar/env/v2\"\n\ntype Config struct {\n\tPort env.Optional[int] `env:\"PORT\"`\n}", "field Port has unsupported type env.Optional[int]"},
+		{"type Config int", "type Config is not a struct"},
+		{"type Other struct{}", "type Config not found in package config"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+
+		if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte("package config\n\n"+test.src+"\n"), 0o644); err != nil {
+			t.Fatalf("write source failed %s", err.Error())
+		}
+
+		err := run([]string{"-type", "Config"}, dir, io.Discard)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("expected error containing %q got %v", test.expected, err)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "config_env.go")); err == nil {
+			t.Errorf("expected no file to be generated for %q", test.src)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rojbar/env/v2"
+)
+
+// field is a struct field bound to an environment variable.
+type field struct {
+	path         string
+	key          string
+	kind         kind
+	slice        bool
+	separator    string
+	sliceOptions []string
+	indexed      *indexed
+	base         int
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	secret       bool
+	usage        string
+}
+
+// indexed names the variables of the elements of a slice field tagged with envIndexed.
+type indexed struct {
+	separator string
+	start     int
+}
+
+// kind is the type of a scalar value, or of the elements of a slice.
+type kind struct {
+	// getter is the name of the getter without the Get prefix and From suffix, e.g. "Int".
+	getter string
+	// name is the type name used in the documentation, e.g. "int16" or "duration".
+	name string
+	// typ is the reflect type of the underlying value, used to validate defaults.
+	typ reflect.Type
+}
+
+func (k kind) integer() bool {
+	return k.getter == "Int" || k.getter == "Uint"
+}
+
+var basicKinds = map[string]kind{
+	"string":  {"String", "string", reflect.TypeOf("")},
+	"bool":    {"Bool", "bool", reflect.TypeOf(false)},
+	"int":     {"Int", "int", reflect.TypeOf(0)},
+	"int8":    {"Int", "int8", reflect.TypeOf(int8(0))},
+	"int16":   {"Int", "int16", reflect.TypeOf(int16(0))},
+	"int32":   {"Int", "int32", reflect.TypeOf(int32(0))},
+	"int64":   {"Int", "int64", reflect.TypeOf(int64(0))},
+	"uint":    {"Uint", "uint", reflect.TypeOf(uint(0))},
+	"uint8":   {"Uint", "uint8", reflect.TypeOf(uint8(0))},
+	"uint16":  {"Uint", "uint16", reflect.TypeOf(uint16(0))},
+	"uint32":  {"Uint", "uint32", reflect.TypeOf(uint32(0))},
+	"uint64":  {"Uint", "uint64", reflect.TypeOf(uint64(0))},
+	"float32": {"Float", "float32", reflect.TypeOf(float32(0))},
+	"float64": {"Float", "float64", reflect.TypeOf(float64(0))},
+	"byte":    {"Uint", "uint8", reflect.TypeOf(uint8(0))},
+	"rune":    {"Int", "int32", reflect.TypeOf(int32(0))},
+}
+
+var (
+	durationKind = kind{"Duration", "duration", reflect.TypeOf(time.Duration(0))}
+	urlKind      = kind{"URL", "url", reflect.TypeOf(url.URL{})}
+)
+
+// pkg holds the parsed files of the package the generator runs in.
+type pkg struct {
+	fset  *token.FileSet
+	name  string
+	types map[string]typeDecl
+}
+
+type typeDecl struct {
+	spec *ast.TypeSpec
+	file *ast.File
+}
+
+// parsePackage parses the non-test Go files of the directory, skipping the file named by
+// skip, usually the output of a previous run.
+func parsePackage(dir, skip string) (*pkg, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+
+	p := &pkg{fset: token.NewFileSet(), types: make(map[string]typeDecl)}
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || (skip != "" && sameFile(path, skip)) {
+			continue
+		}
+
+		f, err := parser.ParseFile(p.fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.name == "" {
+			p.name = f.Name.Name
+		} else if f.Name.Name != p.name {
+			continue
+		}
+
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				p.types[ts.Name.Name] = typeDecl{spec: ts, file: f}
+			}
+		}
+	}
+
+	if p.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	return p, nil
+}
+
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// fields returns the fields of the struct type named by the name bound to environment
+// variables, following the tag rules of env.Unmarshal.
+func (p *pkg) fields(name string) ([]field, error) {
+	decl, ok := p.types[name]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in package %s", name, p.name)
+	}
+
+	st, ok := decl.spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", name)
+	}
+
+	return p.structFields(st, decl.file, "", "")
+}
+
+func (p *pkg) structFields(st *ast.StructType, file *ast.File, path, prefix string) ([]field, error) {
+	var fields []field
+
+	for _, f := range st.Fields.List {
+		names := make([]string, 0, len(f.Names))
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+
+		if len(names) == 0 {
+			names = append(names, embeddedName(f.Type))
+		}
+
+		tag := reflect.StructTag("")
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, p.errorf(f, "invalid tag %s", f.Tag.Value)
+			}
+
+			tag = reflect.StructTag(unquoted)
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+
+			envTag, tagged := tag.Lookup("env")
+			if envTag == "-" {
+				continue
+			}
+
+			if !tagged {
+				if arr, ok := f.Type.(*ast.ArrayType); ok && arr.Len == nil {
+					if _, _, ok := p.nestedStruct(arr.Elt, file); ok {
+						return nil, p.errorf(f, "field %s: slices of structs are not supported", path+name)
+					}
+				}
+
+				if m, ok := f.Type.(*ast.MapType); ok {
+					if _, _, ok := p.nestedStruct(m.Value, file); ok {
+						return nil, p.errorf(f, "field %s: maps of structs are not supported", path+name)
+					}
+				}
+
+				nested, nestedFile, ok := p.nestedStruct(f.Type, file)
+				if !ok {
+					continue
+				}
+
+				n, err := p.structFields(nested, nestedFile, path+name+".", prefix+tag.Get("envPrefix"))
+				if err != nil {
+					return nil, err
+				}
+
+				fields = append(fields, n...)
+
+				continue
+			}
+
+			fd, err := p.field(f, file, tag, path+name, prefix)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, fd)
+		}
+	}
+
+	return fields, nil
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	default:
+		return ""
+	}
+}
+
+// nestedStruct returns the struct type of an untagged field that is bound recursively.
+func (p *pkg) nestedStruct(expr ast.Expr, file *ast.File) (*ast.StructType, *ast.File, bool) {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t, file, true
+	case *ast.Ident:
+		decl, ok := p.types[t.Name]
+		if !ok {
+			return nil, nil, false
+		}
+
+		return p.nestedStruct(decl.spec.Type, decl.file)
+	default:
+		return nil, nil, false
+	}
+}
+
+func (p *pkg) field(f *ast.Field, file *ast.File, tag reflect.StructTag, path, prefix string) (field, error) {
+	envTag := tag.Get("env")
+
+	key, opts, _ := strings.Cut(envTag, ",")
+	if key == "" {
+		return field{}, p.errorf(f, "field %s has an empty key", path)
+	}
+
+	fd := field{
+		path:      path,
+		key:       prefix + key,
+		separator: ",",
+		base:      10,
+		usage:     strings.Join(strings.Fields(f.Doc.Text()), " "),
+	}
+
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "":
+		case "required":
+			fd.required = true
+		case "secret":
+			fd.secret = true
+		default:
+			return field{}, p.errorf(f, "field %s has unknown option %q", path, opt)
+		}
+	}
+
+	if separator, ok := tag.Lookup("envSeparator"); ok {
+		fd.separator = separator
+	}
+
+	if names, ok := tag.Lookup("envSlice"); ok {
+		for _, name := range strings.Split(names, ",") {
+			if _, ok := sliceOptions[name]; !ok {
+				return field{}, p.errorf(f, "field %s has unknown slice option %q", path, name)
+			}
+
+			fd.sliceOptions = append(fd.sliceOptions, name)
+		}
+	}
+
+	if base, ok := tag.Lookup("envBase"); ok {
+		parsed, err := strconv.Atoi(base)
+		if err != nil || parsed == 1 || parsed < 0 || parsed > 36 {
+			return field{}, p.errorf(f, "field %s has invalid base %q", path, base)
+		}
+
+		fd.base = parsed
+	}
+
+	fd.defaultValue, fd.hasDefault = tag.Lookup("envDefault")
+
+	if fd.usage == "" {
+		fd.usage = tag.Get("envUsage")
+	}
+
+	for _, name := range []string{"envMin", "envMax", "envEnum"} {
+		if _, ok := tag.Lookup(name); ok {
+			return field{}, p.errorf(f, "field %s: the %s tag is not supported", path, name)
+		}
+	}
+
+	typ := f.Type
+	if arr, ok := typ.(*ast.ArrayType); ok && arr.Len == nil {
+		fd.slice = true
+		typ = arr.Elt
+	}
+
+	if separator, ok := tag.Lookup("envIndexed"); ok {
+		if !fd.slice {
+			return field{}, p.errorf(f, "field %s is indexed but is not a slice", path)
+		}
+
+		fd.indexed = &indexed{separator: separator}
+
+		if start, ok := tag.Lookup("envIndexStart"); ok {
+			parsed, err := strconv.Atoi(start)
+			if err != nil || parsed < 0 {
+				return field{}, p.errorf(f, "field %s has invalid index start %q", path, start)
+			}
+
+			fd.indexed.start = parsed
+		}
+
+		// The loader reads defaults by key, while the elements of an indexed slice are read
+		// from one variable each.
+		if fd.hasDefault {
+			return field{}, p.errorf(f, "field %s: defaults of indexed slices are not supported", path)
+		}
+	}
+
+	k, ok := p.kind(typ, file)
+	if !ok {
+		return field{}, p.errorf(f, "field %s has unsupported type %s", path, p.exprString(f.Type))
+	}
+
+	fd.kind = k
+
+	if err := fd.validate(); err != nil {
+		return field{}, p.errorf(f, "%s", err.Error())
+	}
+
+	return fd, nil
+}
+
+// kind returns the kind of a scalar type expression declared in file.
+func (p *pkg) kind(expr ast.Expr, file *ast.File) (kind, bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if decl, ok := p.types[t.Name]; ok {
+			k, ok := p.kind(decl.spec.Type, decl.file)
+			if !ok || decl.spec.Assign.IsValid() {
+				return k, ok
+			}
+
+			// A defined type takes the underlying type of time.Duration or url.URL but not
+			// their methods, so it is bound like env.Unmarshal binds it: as an int64, or not
+			// at all.
+			switch k {
+			case durationKind:
+				return basicKinds["int64"], true
+			case urlKind:
+				return kind{}, false
+			}
+
+			return k, true
+		}
+
+		k, ok := basicKinds[t.Name]
+
+		return k, ok
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return kind{}, false
+		}
+
+		switch importPath(file, x.Name) + "." + t.Sel.Name {
+		case "time.Duration":
+			return durationKind, true
+		case "net/url.URL":
+			return urlKind, true
+		}
+	case *ast.ParenExpr:
+		return p.kind(t.X, file)
+	}
+
+	return kind{}, false
+}
+
+// importPath returns the path of the package imported in file under the name.
+func importPath(file *ast.File, name string) string {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		if imp.Name != nil {
+			if imp.Name.Name == name {
+				return path
+			}
+
+			continue
+		}
+
+		if path[strings.LastIndex(path, "/")+1:] == name {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// validate checks the default value of the field by binding it with env.UnmarshalFrom, so
+// that it is parsed with exactly the rules the getters apply at run time.
+func (fd field) validate() error {
+	typ := fd.kind.typ
+	if fd.slice {
+		typ = reflect.SliceOf(typ)
+	}
+
+	tag := fmt.Sprintf(`env:"K" envSeparator:%q envBase:"%d"`, fd.separator, fd.base)
+	if len(fd.sliceOptions) > 0 {
+		tag += fmt.Sprintf(" envSlice:%q", strings.Join(fd.sliceOptions, ","))
+	}
+
+	if fd.indexed != nil {
+		tag += fmt.Sprintf(` envIndexed:%q envIndexStart:"%d"`, fd.indexed.separator, fd.indexed.start)
+	}
+
+	if fd.hasDefault {
+		tag += fmt.Sprintf(" envDefault:%q", fd.defaultValue)
+	}
+
+	cfg := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "F", Type: typ, Tag: reflect.StructTag(tag)}}))
+
+	if err := env.UnmarshalFrom(env.NewMap(nil), cfg.Interface()); err != nil {
+		return fmt.Errorf("field %s: %s", fd.path, strings.Replace(err.Error(), "env: invalid default for K", "invalid default", 1))
+	}
+
+	return nil
+}
+
+func (p *pkg) errorf(node ast.Node, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", p.fset.Position(node.Pos()), fmt.Sprintf(format, args...))
+}
+
+func (p *pkg) exprString(expr ast.Expr) string {
+	var b strings.Builder
+	if err := printer.Fprint(&b, p.fset, expr); err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+
+	return b.String()
+}
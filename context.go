@@ -0,0 +1,317 @@
+package env
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ProvenanceContext is recorded for values read from an overlay attached to a context with
+// [WithValues].
+const ProvenanceContext = "context"
+
+type overlayKey struct{}
+
+// WithSource returns a copy of ctx carrying src as an overlay. The *Ctx getters resolve
+// variables through the overlays of their context before falling back to the package
+// [Source], so per-request configuration can be overridden without changing the process
+// environment. Overlays attached later take precedence over overlays attached earlier.
+// Values read from an overlay are specific to the context, so they are neither recorded in
+// the [ProvenanceReport] nor mark their key as read for [UnknownKeys].
+func WithSource(ctx context.Context, src Source) context.Context {
+	parent, _ := ctx.Value(overlayKey{}).([]Source)
+
+	overlays := make([]Source, len(parent), len(parent)+1)
+	copy(overlays, parent)
+
+	return context.WithValue(ctx, overlayKey{}, append(overlays, src))
+}
+
+// WithValues is like [WithSource] with an overlay holding a copy of the values.
+func WithValues(ctx context.Context, values map[string]string) context.Context {
+	return WithSource(ctx, newMap(values, ProvenanceContext))
+}
+
+// SourceFromContext returns the [Source] the *Ctx getters resolve variables through: the
+// overlays attached to ctx stacked over the package [Source]. If ctx carries no overlays the
+// package source is returned.
+func SourceFromContext(ctx context.Context) Source {
+	overlays, _ := ctx.Value(overlayKey{}).([]Source)
+	if len(overlays) == 0 {
+		return currentSource()
+	}
+
+	return contextSource{overlays: overlays, base: currentSource()}
+}
+
+// contextSource resolves keys through the overlays of a context in decreasing order of
+// precedence, then through the base source.
+type contextSource struct {
+	overlays []Source
+	base     Source
+}
+
+func (s contextSource) resolve(key string) Source {
+	if overlay, ok := s.overlay(key); ok {
+		return overlay
+	}
+
+	return s.base
+}
+
+// overlay returns the overlay of highest precedence holding the key, if any.
+func (s contextSource) overlay(key string) (Source, bool) {
+	for i := len(s.overlays) - 1; i >= 0; i-- {
+		if _, ok := s.overlays[i].Lookup(key); ok {
+			return s.overlays[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// overlaid reports whether the variable named by the key is resolved through an overlay of a
+// context. Overlays hold per-request values, so reading them is neither recorded as a read of
+// the key nor in the [ProvenanceReport] of the process.
+func overlaid(src Source, key string) bool {
+	s, ok := src.(contextSource)
+	if !ok {
+		return false
+	}
+
+	_, ok = s.overlay(key)
+
+	return ok
+}
+
+func (s contextSource) Lookup(key string) (string, bool) {
+	return s.resolve(key).Lookup(key)
+}
+
+func (s contextSource) Keys() []string {
+	seen := make(map[string]struct{})
+
+	var keys []string
+
+	for _, src := range append([]Source{s.base}, s.overlays...) {
+		for _, key := range src.Keys() {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (s contextSource) Provenance(key string) Provenance {
+	return provenanceOf(s.resolve(key), key)
+}
+
+// GetStringCtx is like [GetString] but resolves the variable through the overlays attached to
+// ctx with [WithSource] before the package [Source].
+func GetStringCtx[V String](ctx context.Context, key string, defaultValue V) V {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, parseString[V])
+}
+
+// GetBoolCtx is like [GetBool] but resolves the variable through the overlays attached to ctx
+// with [WithSource] before the package [Source].
+func GetBoolCtx[V Boolean](ctx context.Context, key string, defaultValue V) V {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, parseBool[V])
+}
+
+// GetIntCtx is like [GetInt] but resolves the variable through the overlays attached to ctx
+// with [WithSource] before the package [Source].
+func GetIntCtx[V Signed](ctx context.Context, key string, base int, defaultValue V) V {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, intParser[V](base))
+}
+
+// GetUintCtx is like [GetUint] but resolves the variable through the overlays attached to ctx
+// with [WithSource] before the package [Source].
+func GetUintCtx[V Unsigned](ctx context.Context, key string, base int, defaultValue V) V {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, uintParser[V](base))
+}
+
+// GetFloatCtx is like [GetFloat] but resolves the variable through the overlays attached to
+// ctx with [WithSource] before the package [Source].
+func GetFloatCtx[V Float](ctx context.Context, key string, defaultValue V) V {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, parseFloat[V])
+}
+
+// GetDurationCtx is like [GetDuration] but resolves the variable through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetDurationCtx(ctx context.Context, key string, defaultValue time.Duration) time.Duration {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, time.ParseDuration)
+}
+
+// GetURLCtx is like [GetURL] but resolves the variable through the overlays attached to ctx
+// with [WithSource] before the package [Source].
+func GetURLCtx(ctx context.Context, key string, defaultValue url.URL) url.URL {
+	return getFrom(SourceFromContext(ctx), key, defaultValue, parseURL)
+}
+
+// GetStringSliceCtx is like [GetStringSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func GetStringSliceCtx[V String](ctx context.Context, key, separator string, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(SourceFromContext(ctx), key, separator, defaultValue, parseString[V], opts)
+}
+
+// GetBoolSliceCtx is like [GetBoolSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func GetBoolSliceCtx[V Boolean](ctx context.Context, key, separator string, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(SourceFromContext(ctx), key, separator, defaultValue, parseBool[V], opts)
+}
+
+// GetIntSliceCtx is like [GetIntSlice] but resolves the variable through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetIntSliceCtx[V Signed](ctx context.Context, key, separator string, base int, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(SourceFromContext(ctx), key, separator, defaultValue, intParser[V](base), opts)
+}
+
+// GetUintSliceCtx is like [GetUintSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func GetUintSliceCtx[V Unsigned](ctx context.Context, key, separator string, base int, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(SourceFromContext(ctx), key, separator, defaultValue, uintParser[V](base), opts)
+}
+
+// GetDurationSliceCtx is like [GetDurationSlice] but resolves the variable through the
+// overlays attached to ctx with [WithSource] before the package [Source].
+func GetDurationSliceCtx(ctx context.Context, key, separator string, defaultValue []time.Duration, opts ...SliceOption) []time.Duration {
+	return getSlice(SourceFromContext(ctx), key, separator, defaultValue, time.ParseDuration, opts)
+}
+
+// GetURLSliceCtx is like [GetURLSlice] but resolves the variable through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetURLSliceCtx(ctx context.Context, key, separator string, defaultValue []url.URL, opts ...SliceOption) []url.URL {
+	return getSlice(SourceFromContext(ctx), key, separator, defaultValue, parseURL, opts)
+}
+
+// GetStringMapCtx is like [GetStringMap] but resolves the variables through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func GetStringMapCtx[V String](ctx context.Context, prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, parseString[V])
+}
+
+// GetBoolMapCtx is like [GetBoolMap] but resolves the variables through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetBoolMapCtx[V Boolean](ctx context.Context, prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, parseBool[V])
+}
+
+// GetIntMapCtx is like [GetIntMap] but resolves the variables through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetIntMapCtx[V Signed](ctx context.Context, prefix, separator string, base int, defaultValue map[string]V) map[string]V {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, intParser[V](base))
+}
+
+// GetUintMapCtx is like [GetUintMap] but resolves the variables through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetUintMapCtx[V Unsigned](ctx context.Context, prefix, separator string, base int, defaultValue map[string]V) map[string]V {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, uintParser[V](base))
+}
+
+// GetFloatMapCtx is like [GetFloatMap] but resolves the variables through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func GetFloatMapCtx[V Float](ctx context.Context, prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, parseFloat[V])
+}
+
+// GetDurationMapCtx is like [GetDurationMap] but resolves the variables through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func GetDurationMapCtx(ctx context.Context, prefix, separator string, defaultValue map[string]time.Duration) map[string]time.Duration {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, time.ParseDuration)
+}
+
+// GetURLMapCtx is like [GetURLMap] but resolves the variables through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func GetURLMapCtx(ctx context.Context, prefix, separator string, defaultValue map[string]url.URL) map[string]url.URL {
+	return getMap(SourceFromContext(ctx), prefix, separator, defaultValue, parseURL)
+}
+
+// MaybeStringCtx is like [MaybeString] but resolves the variable through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func MaybeStringCtx[V String](ctx context.Context, key string) Optional[V] {
+	return maybe(SourceFromContext(ctx), key, parseString[V])
+}
+
+// MaybeBoolCtx is like [MaybeBool] but resolves the variable through the overlays attached to
+// ctx with [WithSource] before the package [Source].
+func MaybeBoolCtx[V Boolean](ctx context.Context, key string) Optional[V] {
+	return maybe(SourceFromContext(ctx), key, parseBool[V])
+}
+
+// MaybeIntCtx is like [MaybeInt] but resolves the variable through the overlays attached to
+// ctx with [WithSource] before the package [Source].
+func MaybeIntCtx[V Signed](ctx context.Context, key string, base int) Optional[V] {
+	return maybe(SourceFromContext(ctx), key, intParser[V](base))
+}
+
+// MaybeUintCtx is like [MaybeUint] but resolves the variable through the overlays attached to
+// ctx with [WithSource] before the package [Source].
+func MaybeUintCtx[V Unsigned](ctx context.Context, key string, base int) Optional[V] {
+	return maybe(SourceFromContext(ctx), key, uintParser[V](base))
+}
+
+// MaybeFloatCtx is like [MaybeFloat] but resolves the variable through the overlays attached
+// to ctx with [WithSource] before the package [Source].
+func MaybeFloatCtx[V Float](ctx context.Context, key string) Optional[V] {
+	return maybe(SourceFromContext(ctx), key, parseFloat[V])
+}
+
+// MaybeDurationCtx is like [MaybeDuration] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func MaybeDurationCtx(ctx context.Context, key string) Optional[time.Duration] {
+	return maybe(SourceFromContext(ctx), key, time.ParseDuration)
+}
+
+// MaybeURLCtx is like [MaybeURL] but resolves the variable through the overlays attached to
+// ctx with [WithSource] before the package [Source].
+func MaybeURLCtx(ctx context.Context, key string) Optional[url.URL] {
+	return maybe(SourceFromContext(ctx), key, parseURL)
+}
+
+// MaybeStringSliceCtx is like [MaybeStringSlice] but resolves the variable through the
+// overlays attached to ctx with [WithSource] before the package [Source].
+func MaybeStringSliceCtx[V String](ctx context.Context, key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, parseString[V], opts)
+}
+
+// MaybeBoolSliceCtx is like [MaybeBoolSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func MaybeBoolSliceCtx[V Boolean](ctx context.Context, key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, parseBool[V], opts)
+}
+
+// MaybeIntSliceCtx is like [MaybeIntSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func MaybeIntSliceCtx[V Signed](ctx context.Context, key, separator string, base int, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, intParser[V](base), opts)
+}
+
+// MaybeUintSliceCtx is like [MaybeUintSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func MaybeUintSliceCtx[V Unsigned](ctx context.Context, key, separator string, base int, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, uintParser[V](base), opts)
+}
+
+// MaybeFloatSliceCtx is like [MaybeFloatSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func MaybeFloatSliceCtx[V Float](ctx context.Context, key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, parseFloat[V], opts)
+}
+
+// MaybeDurationSliceCtx is like [MaybeDurationSlice] but resolves the variable through the
+// overlays attached to ctx with [WithSource] before the package [Source].
+func MaybeDurationSliceCtx(ctx context.Context, key, separator string, opts ...SliceOption) Optional[[]time.Duration] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, time.ParseDuration, opts)
+}
+
+// MaybeURLSliceCtx is like [MaybeURLSlice] but resolves the variable through the overlays
+// attached to ctx with [WithSource] before the package [Source].
+func MaybeURLSliceCtx(ctx context.Context, key, separator string, opts ...SliceOption) Optional[[]url.URL] {
+	return maybeSlice(SourceFromContext(ctx), key, separator, parseURL, opts)
+}
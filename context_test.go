@@ -0,0 +1,82 @@
+package env
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestContextOverlay(t *testing.T) {
+	SetSource(NewMap(map[string]string{
+		"KEY_CTX_NAME":    "base",
+		"KEY_CTX_TIMEOUT": "1s",
+	}))
+	t.Cleanup(func() { SetSource(nil) })
+
+	ctx := context.Background()
+
+	if val := GetStringCtx(ctx, "KEY_CTX_NAME", ""); val != "base" {
+		t.Errorf("expected base got %s", val)
+	}
+
+	tenant := WithValues(ctx, map[string]string{"KEY_CTX_NAME": "tenant", "KEY_CTX_PORT": "8080"})
+	request := WithSource(tenant, NewMap(map[string]string{"KEY_CTX_PORT": "9090"}))
+
+	if val := GetStringCtx(tenant, "KEY_CTX_NAME", ""); val != "tenant" {
+		t.Errorf("expected tenant got %s", val)
+	}
+
+	if val := GetIntCtx(tenant, "KEY_CTX_PORT", 10, 0); val != 8080 {
+		t.Errorf("expected 8080 got %d", val)
+	}
+
+	if val := GetIntCtx(request, "KEY_CTX_PORT", 10, 0); val != 9090 {
+		t.Errorf("expected 9090 got %d", val)
+	}
+
+	if val := GetStringCtx(request, "KEY_CTX_NAME", ""); val != "tenant" {
+		t.Errorf("expected tenant got %s", val)
+	}
+
+	if val := GetDurationCtx(request, "KEY_CTX_TIMEOUT", 0); val != time.Second {
+		t.Errorf("expected 1s got %s", val)
+	}
+
+	if val := GetStringCtx(ctx, "KEY_CTX_NAME", ""); val != "base" {
+		t.Errorf("expected overlays not to affect the parent context got %s", val)
+	}
+
+	keys := SourceFromContext(request).Keys()
+	if err := equalSlices(keys, []string{"KEY_CTX_NAME", "KEY_CTX_PORT", "KEY_CTX_TIMEOUT"}); err != nil {
+		t.Errorf("expected keys of all overlays got %v: %s", keys, err.Error())
+	}
+
+	GetStringCtx(ctx, "KEY_CTX_NAME", "")
+	GetStringCtx(tenant, "KEY_CTX_NAME", "")
+	if p := provenanceFor("KEY_CTX_NAME"); p.Source != ProvenanceMap {
+		t.Errorf("expected overlay reads not to be recorded got %s", p.Source)
+	}
+
+	if p := SourceFromContext(tenant).(ProvenanceSource).Provenance("KEY_CTX_NAME"); p.Source != ProvenanceContext {
+		t.Errorf("expected provenance %s got %s", ProvenanceContext, p.Source)
+	}
+
+	if _, ok := readKeys.Load("KEY_CTX_PORT"); ok {
+		t.Errorf("expected overlay key not to be recorded as read")
+	}
+}
+
+func TestContextOverlaySiblings(t *testing.T) {
+	ctx := WithValues(context.Background(), map[string]string{"KEY_CTX_SIBLING": "parent"})
+
+	a := WithValues(ctx, map[string]string{"KEY_CTX_SIBLING": "a"})
+	b := WithValues(ctx, map[string]string{"KEY_CTX_SIBLING": "b"})
+
+	if val := GetStringCtx(a, "KEY_CTX_SIBLING", ""); val != "a" {
+		t.Errorf("expected a got %s", val)
+	}
+
+	if val := GetStringCtx(b, "KEY_CTX_SIBLING", ""); val != "b" {
+		t.Errorf("expected b got %s", val)
+	}
+}
@@ -0,0 +1,187 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the kind of a [Change] between two sources.
+type ChangeKind string
+
+const (
+	// ChangeAdded is reported for a variable present only in the after source.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is reported for a variable present only in the before source.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeChanged is reported for a variable present in both sources with different values.
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a difference between two sources reported by [Diff].
+type Change struct {
+	// Key is the name of the environment variable.
+	Key string `json:"key"`
+	// Kind is the kind of the change.
+	Kind ChangeKind `json:"kind"`
+	// Old is the value in the before source, empty if the variable was added.
+	Old string `json:"old,omitempty"`
+	// New is the value in the after source, empty if the variable was removed.
+	New string `json:"new,omitempty"`
+}
+
+// String formats the change the way [WriteDiff] writes it.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s=%q", c.Key, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s=%q", c.Key, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %q -> %q", c.Key, c.Old, c.New)
+	}
+}
+
+type diffOptions struct {
+	typed         bool
+	revealSecrets bool
+}
+
+// DiffOption configures how [Diff] compares two sources.
+type DiffOption func(*diffOptions)
+
+// DiffTyped compares the values of declared variables by their parsed form, so that values
+// the getter of the variable treats the same, e.g. 1s and 1000ms for a duration or true and
+// 1 for a bool, are not reported as changed. Values of undeclared variables, and values that
+// can not be parsed, are compared as strings.
+func DiffTyped() DiffOption {
+	return func(o *diffOptions) {
+		o.typed = true
+	}
+}
+
+// DiffRevealSecrets reports the values of secret variables, which are redacted by default.
+func DiffRevealSecrets() DiffOption {
+	return func(o *diffOptions) {
+		o.revealSecrets = true
+	}
+}
+
+// Diff compares the variables of the before and after sources and returns the added,
+// removed and changed variables in lexicographical order of their keys. Values of variables marked as
+// secret with [Var.MarkSecret], or bound to struct fields tagged as secret, are redacted
+// unless [DiffRevealSecrets] is passed, but are still compared.
+func Diff(before, after Source, opts ...DiffOption) []Change {
+	var o diffOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	keys := make(map[string]struct{})
+	for _, key := range before.Keys() {
+		keys[key] = struct{}{}
+	}
+
+	for _, key := range after.Keys() {
+		keys[key] = struct{}{}
+	}
+
+	var changes []Change
+
+	for key := range keys {
+		oldVal, inOld := before.Lookup(key)
+		newVal, inNew := after.Lookup(key)
+
+		var change Change
+
+		switch {
+		case inOld && inNew:
+			if equalValues(key, oldVal, newVal, o.typed) {
+				continue
+			}
+
+			change = Change{Key: key, Kind: ChangeChanged, Old: oldVal, New: newVal}
+		case inOld:
+			change = Change{Key: key, Kind: ChangeRemoved, Old: oldVal}
+		case inNew:
+			change = Change{Key: key, Kind: ChangeAdded, New: newVal}
+		default:
+			continue
+		}
+
+		if !o.revealSecrets && isSecret(key) {
+			if inOld {
+				change.Old = redacted
+			}
+
+			if inNew {
+				change.New = redacted
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+func equalValues(key, a, b string, typed bool) bool {
+	if a == b {
+		return true
+	}
+
+	if !typed {
+		return false
+	}
+
+	parse := variableParser(key)
+	if parse == nil {
+		return false
+	}
+
+	parsedA, err := parse(a)
+	if err != nil {
+		return false
+	}
+
+	parsedB, err := parse(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(parsedA, parsedB)
+}
+
+// WriteDiff writes the changes to w, one change per line. Added variables are prefixed with
+// +, removed variables with - and changed variables with ~.
+func WriteDiff(w io.Writer, changes []Change) error {
+	var b strings.Builder
+
+	for _, c := range changes {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteDiffJSON writes the changes to w as a JSON array.
+func WriteDiffJSON(w io.Writer, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(changes)
+}
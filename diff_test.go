@@ -0,0 +1,91 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	isolateRegistry(t)
+
+	DurationVar("KEY_DIFF_TIMEOUT", time.Second, "timeout")
+	BoolVar("KEY_DIFF_DEBUG", false, "debug")
+	StringVar("KEY_DIFF_PASSWORD", "", "password").MarkSecret()
+
+	before := NewMap(map[string]string{
+		"KEY_DIFF_TIMEOUT":  "1s",
+		"KEY_DIFF_DEBUG":    "true",
+		"KEY_DIFF_PASSWORD": "hunter2",
+		"KEY_DIFF_REMOVED":  "gone",
+		"KEY_DIFF_SAME":     "same",
+	})
+	after := NewMap(map[string]string{
+		"KEY_DIFF_TIMEOUT":  "1000ms",
+		"KEY_DIFF_DEBUG":    "1",
+		"KEY_DIFF_PASSWORD": "hunter3",
+		"KEY_DIFF_ADDED":    "new",
+		"KEY_DIFF_SAME":     "same",
+	})
+
+	expected := []Change{
+		{Key: "KEY_DIFF_ADDED", Kind: ChangeAdded, New: "new"},
+		{Key: "KEY_DIFF_DEBUG", Kind: ChangeChanged, Old: "true", New: "1"},
+		{Key: "KEY_DIFF_PASSWORD", Kind: ChangeChanged, Old: redacted, New: redacted},
+		{Key: "KEY_DIFF_REMOVED", Kind: ChangeRemoved, Old: "gone"},
+		{Key: "KEY_DIFF_TIMEOUT", Kind: ChangeChanged, Old: "1s", New: "1000ms"},
+	}
+
+	if err := equalSlices(Diff(before, after), expected); err != nil {
+		t.Errorf("expected changes %v got %v: %s", expected, Diff(before, after), err.Error())
+	}
+
+	expected = []Change{
+		{Key: "KEY_DIFF_ADDED", Kind: ChangeAdded, New: "new"},
+		{Key: "KEY_DIFF_PASSWORD", Kind: ChangeChanged, Old: "hunter2", New: "hunter3"},
+		{Key: "KEY_DIFF_REMOVED", Kind: ChangeRemoved, Old: "gone"},
+	}
+
+	changes := Diff(before, after, DiffTyped(), DiffRevealSecrets())
+	if err := equalSlices(changes, expected); err != nil {
+		t.Errorf("expected typed changes %v got %v: %s", expected, changes, err.Error())
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	changes := []Change{
+		{Key: "ADDED", Kind: ChangeAdded, New: "new value"},
+		{Key: "CHANGED", Kind: ChangeChanged, Old: "a", New: "b"},
+		{Key: "REMOVED", Kind: ChangeRemoved, Old: "old"},
+	}
+
+	var b bytes.Buffer
+	if err := WriteDiff(&b, changes); err != nil {
+		t.Errorf("write diff failed %s", err.Error())
+	}
+
+	expected := "+ ADDED=\"new value\"\n~ CHANGED: \"a\" -> \"b\"\n- REMOVED=\"old\"\n"
+	if b.String() != expected {
+		t.Errorf("expected %q got %q", expected, b.String())
+	}
+
+	b.Reset()
+	if err := WriteDiffJSON(&b, changes); err != nil {
+		t.Errorf("write diff json failed %s", err.Error())
+	}
+
+	var decoded []Change
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+		t.Errorf("decode failed %s", err.Error())
+	}
+
+	if err := equalSlices(decoded, changes); err != nil {
+		t.Errorf("expected %v got %v: %s", changes, decoded, err.Error())
+	}
+
+	b.Reset()
+	if err := WriteDiffJSON(&b, nil); err != nil || b.String() != "[]\n" {
+		t.Errorf("expected empty array got %q", b.String())
+	}
+}
@@ -0,0 +1,162 @@
+package env
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProvenanceDir is recorded for values read from a file of a [Dir].
+const ProvenanceDir = "dir"
+
+// dataDir is the symlink Kubernetes atomically swaps to publish a new version of a mounted
+// ConfigMap or Secret.
+const dataDir = "..data"
+
+// Dir is a [Source] holding the contents of a directory in which every file name is a key
+// and the contents of the file are its value, the layout used when Kubernetes ConfigMaps and
+// Secrets are mounted as volumes.
+type Dir struct {
+	path   string
+	keys   []string
+	values map[string]string
+	files  map[string]string
+}
+
+type dirOptions struct {
+	keyMapper     func(name string) string
+	trimNewlines  bool
+	includeHidden bool
+}
+
+// DirOption configures how [LoadDir] reads a directory.
+type DirOption func(*dirOptions)
+
+// DirKeyMapper maps the file names of a directory to keys with fn, for example
+// [UpperSnakeKey]. By default file names are used as keys unchanged.
+func DirKeyMapper(fn func(name string) string) DirOption {
+	return func(o *dirOptions) {
+		o.keyMapper = fn
+	}
+}
+
+// DirTrimNewlines removes all trailing newlines from the contents of the files.
+func DirTrimNewlines() DirOption {
+	return func(o *dirOptions) {
+		o.trimNewlines = true
+	}
+}
+
+// DirIncludeHidden includes files whose name starts with a dot, which are ignored by default.
+func DirIncludeHidden() DirOption {
+	return func(o *dirOptions) {
+		o.includeHidden = true
+	}
+}
+
+// UpperSnakeKey maps a file name such as db-password or db.password to DB_PASSWORD.
+func UpperSnakeKey(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// LoadDir reads every regular file directly inside the directory named by the path.
+// Subdirectories are skipped.
+//
+// If the directory contains a ..data symlink, as Kubernetes creates for mounted volumes, the
+// symlink is resolved once and all files are read from its target, so the values always
+// come from a single published version even if the volume is updated while it is read.
+func LoadDir(path string, opts ...DirOption) (*Dir, error) {
+	var o dirOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dir := path
+
+	if _, err := os.Lstat(filepath.Join(path, dataDir)); err == nil {
+		resolved, err := filepath.EvalSymlinks(filepath.Join(path, dataDir))
+		if err != nil {
+			return nil, err
+		}
+
+		dir = resolved
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Dir{
+		path:   path,
+		values: make(map[string]string),
+		files:  make(map[string]string),
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "..") || (!o.includeHidden && strings.HasPrefix(name, ".")) {
+			continue
+		}
+
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		val := string(contents)
+		if o.trimNewlines {
+			val = strings.TrimRight(val, "\r\n")
+		}
+
+		key := name
+		if o.keyMapper != nil {
+			key = o.keyMapper(name)
+		}
+
+		if _, ok := d.values[key]; !ok {
+			d.keys = append(d.keys, key)
+		}
+
+		d.values[key] = val
+		d.files[key] = filepath.Join(path, name)
+	}
+
+	sort.Strings(d.keys)
+
+	return d, nil
+}
+
+// Lookup retrieves the value of the variable named by the key.
+func (d *Dir) Lookup(key string) (string, bool) {
+	val, ok := d.values[key]
+
+	return val, ok
+}
+
+// Keys returns the keys of all variables in lexicographical order.
+func (d *Dir) Keys() []string {
+	keys := make([]string, len(d.keys))
+	copy(keys, d.keys)
+
+	return keys
+}
+
+// Provenance returns the file the variable named by the key was read from.
+func (d *Dir) Provenance(key string) Provenance {
+	return Provenance{Key: key, Source: ProvenanceDir, File: d.files[key]}
+}
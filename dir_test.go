@@ -0,0 +1,90 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600); err != nil {
+			t.Fatalf("write file failed %s", err.Error())
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	path := t.TempDir()
+	writeFiles(t, path, map[string]string{
+		"db-password": "hunter2\n",
+		"log.level":   "debug",
+		".hidden":     "x",
+	})
+
+	if err := os.Mkdir(filepath.Join(path, "nested"), 0o700); err != nil {
+		t.Fatalf("mkdir failed %s", err.Error())
+	}
+
+	d, err := LoadDir(path, DirKeyMapper(UpperSnakeKey), DirTrimNewlines())
+	if err != nil {
+		t.Fatalf("load dir failed %s", err.Error())
+	}
+
+	if err := equalSlices(d.Keys(), []string{"DB_PASSWORD", "LOG_LEVEL"}); err != nil {
+		t.Errorf("expected keys %s", err.Error())
+	}
+
+	if val := GetStringFrom(d, "DB_PASSWORD", ""); val != "hunter2" {
+		t.Errorf("expected trimmed value %q got %q", "hunter2", val)
+	}
+
+	if p := d.Provenance("LOG_LEVEL"); p.Source != ProvenanceDir || p.File != filepath.Join(path, "log.level") {
+		t.Errorf("unexpected provenance %s", p)
+	}
+
+	d, err = LoadDir(path, DirIncludeHidden())
+	if err != nil {
+		t.Fatalf("load dir failed %s", err.Error())
+	}
+
+	if val, ok := d.Lookup(".hidden"); !ok || val != "x" {
+		t.Errorf("expected hidden file value %q got %q", "x", val)
+	}
+
+	if val, _ := d.Lookup("db-password"); val != "hunter2\n" {
+		t.Errorf("expected untrimmed value got %q", val)
+	}
+}
+
+func TestLoadDirDataSymlink(t *testing.T) {
+	path := t.TempDir()
+
+	version := filepath.Join(path, "..2024_01_01")
+	if err := os.Mkdir(version, 0o700); err != nil {
+		t.Fatalf("mkdir failed %s", err.Error())
+	}
+
+	writeFiles(t, version, map[string]string{"level": "info"})
+
+	if err := os.Symlink("..2024_01_01", filepath.Join(path, "..data")); err != nil {
+		t.Skipf("symlinks not supported %s", err.Error())
+	}
+
+	if err := os.Symlink(filepath.Join("..data", "level"), filepath.Join(path, "level")); err != nil {
+		t.Fatalf("symlink failed %s", err.Error())
+	}
+
+	d, err := LoadDir(path)
+	if err != nil {
+		t.Fatalf("load dir failed %s", err.Error())
+	}
+
+	if err := equalSlices(d.Keys(), []string{"level"}); err != nil {
+		t.Errorf("expected keys %s", err.Error())
+	}
+
+	if val, _ := d.Lookup("level"); val != "info" {
+		t.Errorf("expected value %q got %q", "info", val)
+	}
+}
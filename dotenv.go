@@ -0,0 +1,163 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Dotenv is a [Source] holding the variables defined in a dotenv file.
+//
+// Every non blank line not starting with # is an assignment of the form KEY=value,
+// optionally preceded by export. Unquoted values are trimmed and end at a # preceded by
+// whitespace. Values in single quotes are taken literally, values in double quotes support
+// the escape sequences of Go string literals. Quoted values may span multiple lines.
+// Variables are not expanded. When a key is assigned more than once the last assignment
+// wins.
+type Dotenv struct {
+	name   string
+	keys   []string
+	values map[string]string
+	lines  map[string]int
+}
+
+// LoadDotenv reads the dotenv file named by the path.
+func LoadDotenv(path string) (*Dotenv, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseDotenv(f, path)
+}
+
+// ParseDotenv reads a dotenv file from r. The name is used in errors and as the file of the
+// provenance of the values.
+func ParseDotenv(r io.Reader, name string) (*Dotenv, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Dotenv{
+		name:   name,
+		values: make(map[string]string),
+		lines:  make(map[string]int),
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+
+		line := strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(line) == "" || line[0] == '#' {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "export "); ok {
+			line = strings.TrimLeft(rest, " \t")
+		}
+
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("env: %s:%d: missing = in assignment", name, lineNo)
+		}
+
+		key = strings.TrimSpace(key)
+		if !validDotenvKey(key) {
+			return nil, fmt.Errorf("env: %s:%d: invalid key %q", name, lineNo, key)
+		}
+
+		val = strings.TrimLeft(val, " \t")
+
+		if val != "" && (val[0] == '"' || val[0] == '\'') {
+			quote := val[0]
+			body := val[1:]
+
+			end := closingQuote(body, quote)
+			for end < 0 && i+1 < len(lines) {
+				i++
+				body += "\n" + lines[i]
+				end = closingQuote(body, quote)
+			}
+
+			if end < 0 {
+				return nil, fmt.Errorf("env: %s:%d: unterminated quoted value", name, lineNo)
+			}
+
+			if rest := strings.TrimSpace(body[end+1:]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("env: %s:%d: unexpected characters after quoted value", name, lineNo)
+			}
+
+			val = body[:end]
+			if quote == '"' {
+				val, err = strconv.Unquote(`"` + strings.ReplaceAll(val, "\n", `\n`) + `"`)
+				if err != nil {
+					return nil, fmt.Errorf("env: %s:%d: invalid quoted value: %w", name, lineNo, err)
+				}
+			}
+		} else {
+			if idx := strings.Index(val, " #"); idx >= 0 {
+				val = val[:idx]
+			}
+
+			if idx := strings.Index(val, "\t#"); idx >= 0 {
+				val = val[:idx]
+			}
+
+			val = strings.TrimSpace(val)
+		}
+
+		if _, ok := d.values[key]; !ok {
+			d.keys = append(d.keys, key)
+		}
+
+		d.values[key] = val
+		d.lines[key] = lineNo
+	}
+
+	return d, nil
+}
+
+// closingQuote returns the index of the first unescaped quote in s, or -1 if there is none.
+// Backslashes only escape inside double quotes.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+func validDotenvKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, " \t\"'=#")
+}
+
+// Lookup retrieves the value of the variable named by the key.
+func (d *Dotenv) Lookup(key string) (string, bool) {
+	val, ok := d.values[key]
+
+	return val, ok
+}
+
+// Keys returns the keys of all variables in the order they were first assigned.
+func (d *Dotenv) Keys() []string {
+	keys := make([]string, len(d.keys))
+	copy(keys, d.keys)
+
+	return keys
+}
+
+// Provenance returns the file and line the variable named by the key was last assigned on.
+func (d *Dotenv) Provenance(key string) Provenance {
+	return Provenance{Key: key, Source: ProvenanceDotenv, File: d.name, Line: d.lines[key]}
+}
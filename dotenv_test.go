@@ -0,0 +1,64 @@
+package env
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadDotenv(t *testing.T) {
+	d, err := LoadDotenv("testdata/app.env")
+	if err != nil {
+		t.Fatalf("load dotenv failed %s", err.Error())
+	}
+
+	tests := []struct {
+		key string
+		val string
+	}{
+		{"APP_NAME", "rojbar"},
+		{"APP_PORT", "8080"},
+		{"APP_GREETING", "hello\tworld"},
+		{"APP_LITERAL", `no \n escapes`},
+		{"APP_CERT", "-----BEGIN-----\nabc\n-----END-----"},
+		{"APP_EMPTY", ""},
+	}
+
+	for _, test := range tests {
+		val, ok := d.Lookup(test.key)
+		if !ok {
+			t.Errorf("expected key %s to be present", test.key)
+		}
+
+		if val != test.val {
+			t.Errorf("expected key %s value %q got %q", test.key, test.val, val)
+		}
+	}
+
+	if err := equalSlices(d.Keys(), []string{"APP_NAME", "APP_PORT", "APP_GREETING", "APP_LITERAL", "APP_CERT", "APP_EMPTY"}); err != nil {
+		t.Errorf("expected keys in file order %s", err.Error())
+	}
+
+	p := d.Provenance("APP_CERT")
+	if p.Source != ProvenanceDotenv || p.File != "testdata/app.env" || p.Line != 6 {
+		t.Errorf("unexpected provenance %s", p)
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"KEY", "env: test.env:1: missing = in assignment"},
+		{"\nBAD KEY=1", `env: test.env:2: invalid key "BAD KEY"`},
+		{"KEY=\"open", "env: test.env:1: unterminated quoted value"},
+		{"KEY='a' b", "env: test.env:1: unexpected characters after quoted value"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseDotenv(strings.NewReader(test.input), "test.env")
+		if err == nil || err.Error() != test.err {
+			t.Errorf("expected error %q got %v", test.err, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package env
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+// EmptyPolicy controls how the getters handle a variable that is present with an empty
+// value, see [SetEmptyPolicy].
+type EmptyPolicy int32
+
+const (
+	// EmptyAsValue parses an empty value like any other value, which is the default. The
+	// string getters return the empty string, the slice getters split it into a single empty
+	// element and the other getters return the default value with a [*ParseError].
+	EmptyAsValue EmptyPolicy = iota
+	// EmptyAsUnset handles an empty value as if the variable was not present, so the default
+	// value is returned and the variable is reported as missing if it is required.
+	EmptyAsUnset
+	// EmptyAsError rejects an empty value with a [*ParseError] wrapping [ErrEmpty], so the
+	// default value is returned.
+	EmptyAsError
+	// EmptyAsZero returns the zero value of the type for an empty value: the empty string,
+	// false, zero or an empty slice.
+	EmptyAsZero
+)
+
+// ErrEmpty is the error wrapped by the [*ParseError] returned for an empty value under the
+// [EmptyAsError] policy.
+var ErrEmpty = errors.New("empty value")
+
+var emptyPolicy atomic.Int32
+
+// SetEmptyPolicy sets how the getters, the variables declared with the *Var functions,
+// [Unmarshal] and [Loader] handle variables present with an empty value. The policy applies
+// to the resolved value, after aliases are followed. The default is [EmptyAsValue].
+func SetEmptyPolicy(p EmptyPolicy) {
+	emptyPolicy.Store(int32(p))
+}
+
+func currentEmptyPolicy() EmptyPolicy {
+	return EmptyPolicy(emptyPolicy.Load())
+}
+
+// zeroLike returns the zero value of the dynamic type of v, with slices being empty rather
+// than nil. The dynamic type is used so that a value of an interface type, as bound by
+// [Unmarshal], holds the zero value of the field it was read for.
+func zeroLike[V any](v V) V {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return v
+	}
+
+	zero := reflect.New(t).Elem()
+	if t.Kind() == reflect.Slice {
+		zero.Set(reflect.MakeSlice(t, 0, 0))
+	}
+
+	return zero.Interface().(V)
+}
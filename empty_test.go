@@ -0,0 +1,119 @@
+package env
+
+import (
+	"errors"
+	"testing"
+)
+
+func setEmptyPolicy(t *testing.T, p EmptyPolicy) {
+	t.Helper()
+
+	prev := currentEmptyPolicy()
+	SetEmptyPolicy(p)
+	t.Cleanup(func() { SetEmptyPolicy(prev) })
+}
+
+func TestEmptyAsValue(t *testing.T) {
+	src := NewMap(map[string]string{"KEY_EMPTY": ""})
+
+	if val := GetStringFrom(src, "KEY_EMPTY", "default"); val != "" {
+		t.Errorf("expected empty string got %q", val)
+	}
+
+	if val := GetIntFrom(src, "KEY_EMPTY", 10, 42); val != 42 {
+		t.Errorf("expected default value 42 got %d", val)
+	}
+
+	if err := equalSlices(GetStringSliceFrom(src, "KEY_EMPTY", ",", []string{"a"}), []string{""}); err != nil {
+		t.Errorf("expected a single empty element %s", err.Error())
+	}
+}
+
+func TestEmptyAsUnset(t *testing.T) {
+	setEmptyPolicy(t, EmptyAsUnset)
+	src := NewMap(map[string]string{"KEY_EMPTY": ""})
+
+	if val := GetStringFrom(src, "KEY_EMPTY", "default"); val != "default" {
+		t.Errorf("expected default value got %q", val)
+	}
+
+	if err := equalSlices(GetStringSliceFrom(src, "KEY_EMPTY", ",", []string{"a"}), []string{"a"}); err != nil {
+		t.Errorf("expected default value %s", err.Error())
+	}
+
+	if p := provenanceFor("KEY_EMPTY"); p.Source != ProvenanceDefault {
+		t.Errorf("expected provenance %q got %q", ProvenanceDefault, p.Source)
+	}
+
+	l := NewLoader(src, map[string]string{"KEY_EMPTY": "fallback"})
+	l.Require("KEY_EMPTY")
+
+	if val := GetStringFrom(l, "KEY_EMPTY", ""); val != "fallback" {
+		t.Errorf("expected loader default got %q", val)
+	}
+
+	if err := l.Err(); err != nil {
+		t.Errorf("expected no error got %s", err.Error())
+	}
+
+	var cfg struct {
+		Name string `env:"KEY_EMPTY,required"`
+	}
+
+	var requiredErr *RequiredError
+	if err := UnmarshalFrom(src, &cfg); !errors.As(err, &requiredErr) {
+		t.Errorf("expected required error got %v", err)
+	}
+}
+
+func TestEmptyAsError(t *testing.T) {
+	setEmptyPolicy(t, EmptyAsError)
+
+	src := NewMap(map[string]string{"KEY_EMPTY": ""})
+
+	if val := GetStringFrom(src, "KEY_EMPTY", "default"); val != "default" {
+		t.Errorf("expected default value got %q", val)
+	}
+
+	isolateRegistry(t)
+
+	v := StringVar("KEY_EMPTY", "default", "")
+
+	_, err := v.Lookup(src)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "KEY_EMPTY" || !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected parse error wrapping ErrEmpty got %v", err)
+	}
+}
+
+func TestEmptyAsZero(t *testing.T) {
+	setEmptyPolicy(t, EmptyAsZero)
+
+	src := NewMap(map[string]string{"KEY_EMPTY": ""})
+
+	if val := GetIntFrom(src, "KEY_EMPTY", 10, 42); val != 0 {
+		t.Errorf("expected zero got %d", val)
+	}
+
+	if val := GetBoolFrom(src, "KEY_EMPTY", true); val {
+		t.Errorf("expected false got %t", val)
+	}
+
+	if val := GetStringSliceFrom(src, "KEY_EMPTY", ",", []string{"a"}); val == nil || len(val) != 0 {
+		t.Errorf("expected an empty slice got %#v", val)
+	}
+
+	cfg := struct {
+		Port  int      `env:"KEY_EMPTY"`
+		Hosts []string `env:"KEY_EMPTY"`
+	}{Port: 8080, Hosts: []string{"a"}}
+
+	if err := UnmarshalFrom(src, &cfg); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if cfg.Port != 0 || cfg.Hosts == nil || len(cfg.Hosts) != 0 {
+		t.Errorf("expected zero values got %+v", cfg)
+	}
+}
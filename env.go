@@ -2,10 +2,10 @@
 package env
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
-	"os"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -39,12 +39,7 @@ type Unsigned interface {
 // is not present. A variable defined with an empty string wont return a default
 // value.
 func GetString[V String](key string, defaultValue V) V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	return V(val)
+	return get(key, defaultValue, parseString[V])
 }
 
 // GetBool returns the associated [Boolean] value for the provided environment
@@ -52,17 +47,7 @@ func GetString[V String](key string, defaultValue V) V {
 // is not present or the associated value could not be parsed. Refer to [strconv.ParseBool]
 // for supported values.
 func GetBool[V Boolean](key string, defaultValue V) V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	parsedBool, err := strconv.ParseBool(val)
-	if err != nil {
-		return defaultValue
-	}
-
-	return V(parsedBool)
+	return get(key, defaultValue, parseBool[V])
 }
 
 // GetInt returns the associated [Signed] value for the provided environment
@@ -70,33 +55,7 @@ func GetBool[V Boolean](key string, defaultValue V) V {
 // is not present or the associated value could not be parsed. Refer to [strconv.ParseInt]
 // for supported values.
 func GetInt[V Signed](key string, base int, defaultValue V) V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	var parsedInt int64
-	var err error
-
-	switch any(defaultValue).(type) {
-	case int:
-		parsedInt, err = strconv.ParseInt(val, base, 0)
-	case int8:
-		parsedInt, err = strconv.ParseInt(val, base, 8)
-	case int16:
-		parsedInt, err = strconv.ParseInt(val, base, 16)
-	case int32:
-		parsedInt, err = strconv.ParseInt(val, base, 32)
-	case int64:
-		parsedInt, err = strconv.ParseInt(val, base, 64)
-	default:
-		parsedInt, err = strconv.ParseInt(val, base, 64)
-	}
-	if err != nil {
-		return defaultValue
-	}
-
-	return V(parsedInt)
+	return get(key, defaultValue, intParser[V](base))
 }
 
 // GetUint returns the associated [Unsigned] value for the provided environment
@@ -104,33 +63,7 @@ func GetInt[V Signed](key string, base int, defaultValue V) V {
 // is not present or the associated value could not be parsed. Refer to [strconv.ParseUint]
 // for supported values.
 func GetUint[V Unsigned](key string, base int, defaultValue V) V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	var parsedUint uint64
-	var err error
-
-	switch any(defaultValue).(type) {
-	case uint:
-		parsedUint, err = strconv.ParseUint(val, base, 0)
-	case uint8:
-		parsedUint, err = strconv.ParseUint(val, base, 8)
-	case uint16:
-		parsedUint, err = strconv.ParseUint(val, base, 16)
-	case uint32:
-		parsedUint, err = strconv.ParseUint(val, base, 32)
-	case uint64:
-		parsedUint, err = strconv.ParseUint(val, base, 64)
-	default:
-		parsedUint, err = strconv.ParseUint(val, base, 64)
-	}
-	if err != nil {
-		return defaultValue
-	}
-
-	return V(parsedUint)
+	return get(key, defaultValue, uintParser[V](base))
 }
 
 // GetFloat returns the associated [Float] value for the provided environment
@@ -138,27 +71,7 @@ func GetUint[V Unsigned](key string, base int, defaultValue V) V {
 // is not present or the associated value could not be parsed. Refer to [strconv.ParseFloat]
 // for supported values.
 func GetFloat[V Float](key string, defaultValue V) V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	var parsedFloat float64
-	var err error
-
-	switch any(defaultValue).(type) {
-	case float32:
-		parsedFloat, err = strconv.ParseFloat(val, 32)
-	case float64:
-		parsedFloat, err = strconv.ParseFloat(val, 64)
-	default:
-		parsedFloat, err = strconv.ParseFloat(val, 64)
-	}
-	if err != nil {
-		return defaultValue
-	}
-
-	return V(parsedFloat)
+	return get(key, defaultValue, parseFloat[V])
 }
 
 // GetDuration returns the associated [time.Duration] value for the provided environment
@@ -166,17 +79,7 @@ func GetFloat[V Float](key string, defaultValue V) V {
 // is not present or the associated value could not be parsed. Refer to [time.ParseDuration]
 // for supported values.
 func GetDuration(key string, defaultValue time.Duration) time.Duration {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	parsedDuration, err := time.ParseDuration(val)
-	if err != nil {
-		return defaultValue
-	}
-
-	return parsedDuration
+	return get(key, defaultValue, time.ParseDuration)
 }
 
 // GetURL returns the associated [net/url.URL] value for the provided environment
@@ -184,222 +87,545 @@ func GetDuration(key string, defaultValue time.Duration) time.Duration {
 // is not present or the associated value could not be parsed. Refer to [net/url.ParseRequestURI]
 // for supported values.
 func GetURL(key string, defaultValue url.URL) url.URL {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	parsedURL, err := url.ParseRequestURI(val)
-	if err != nil {
-		return defaultValue
-	}
-
-	return *parsedURL
+	return get(key, defaultValue, parseURL)
 }
 
 // GetStringSlice returns the associated [][String] values for the provided environment
 // variable named by the key. The defaultValue is returned only if the environment variables
 // is not present.
-func GetStringSlice[V String](key, separator string, defaultValue []V) []V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
+func GetStringSlice[V String](key, separator string, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(currentSource(), key, separator, defaultValue, parseString[V], opts)
+}
 
-	stringVals := strings.Split(val, separator)
-	stringSlice := make([]V, 0, len(stringVals))
+// GetBoolSlice returns the associated [][Boolean] values for the provided environment
+// variable named by the key. The defaultValue is returned only if the environment variables
+// is not present or any of the associated values could not be parsed. Refer to [strconv.ParseBool]
+// for supported values.
+func GetBoolSlice[V Boolean](key, separator string, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(currentSource(), key, separator, defaultValue, parseBool[V], opts)
+}
 
-	for _, strVal := range stringVals {
-		stringSlice = append(stringSlice, V(strVal))
-	}
+// GetIntSlice returns the associated [][Signed] values for the provided environment
+// variable named by the key. The defaultValue is returned only if the environment variable
+// is not present or any of the associated values could not be parsed. Refer to [strconv.ParseInt]
+// for supported values.
+func GetIntSlice[V Signed](key, separator string, base int, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(currentSource(), key, separator, defaultValue, intParser[V](base), opts)
+}
 
-	return stringSlice
+// GetUintSlice returns the associated [][Unsigned] values for the provided environment
+// variable named by the key. The defaultValue is returned only if the environment variable
+// is not present or any of the associated values could not be parsed. Refer to [strconv.ParseUint]
+// for supported values.
+func GetUintSlice[V Unsigned](key, separator string, base int, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(currentSource(), key, separator, defaultValue, uintParser[V](base), opts)
 }
 
-// GetBoolSlice returns the associated [][Boolean] values for the provided environment
+// GetDurationSlice returns the associated []time.Duration values for the provided environment
 // variable named by the key. The defaultValue is returned only if the environment variables
-// is not present or any of the associated values could not be parsed. Refer to [strconv.ParseBool]
+// is not present or any of the associated values could not be parsed. Refer to [time.ParseDuration]
+// for supported values.
+func GetDurationSlice(key, separator string, defaultValue []time.Duration, opts ...SliceOption) []time.Duration {
+	return getSlice(currentSource(), key, separator, defaultValue, time.ParseDuration, opts)
+}
+
+// GetURLSlice returns the associated [net/url.URL] values for the provided environment
+// variable named by the key. The defaultValue is returned only if the environment variables
+// is not present or any of the associated values could not be parsed. Refer to [net/url.ParseRequestURI]
 // for supported values.
-func GetBoolSlice[V Boolean](key, separator string, defaultValue []V) []V {
-	val, ok := os.LookupEnv(key)
+func GetURLSlice(key, separator string, defaultValue []url.URL, opts ...SliceOption) []url.URL {
+	return getSlice(currentSource(), key, separator, defaultValue, parseURL, opts)
+}
+
+// LookupStringSlice returns the [][String] values of the environment variable named by the key
+// like [GetStringSlice], and reports why they could not be read instead of returning a default
+// value. A nil slice and a nil error are returned if the variable is not present. If the value
+// could not be split or any element could not be parsed, the returned [*ParseError] joins an
+// [*ElementError] for every invalid element, see [ElementErrors], and the valid elements are
+// returned along with it only if invalid elements are skipped with [SliceSkipInvalid].
+func LookupStringSlice[V String](key, separator string, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(currentSource(), key, separator, parseString[V], opts)
+}
+
+// LookupBoolSlice returns the [][Boolean] values of the environment variable named by the key
+// like [GetBoolSlice], and reports the elements that could not be parsed with a [*ParseError]
+// instead of returning a default value, see [LookupStringSlice].
+func LookupBoolSlice[V Boolean](key, separator string, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(currentSource(), key, separator, parseBool[V], opts)
+}
+
+// LookupIntSlice returns the [][Signed] values of the environment variable named by the key
+// like [GetIntSlice], and reports the elements that could not be parsed with a [*ParseError]
+// instead of returning a default value, see [LookupStringSlice].
+func LookupIntSlice[V Signed](key, separator string, base int, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(currentSource(), key, separator, intParser[V](base), opts)
+}
+
+// LookupUintSlice returns the [][Unsigned] values of the environment variable named by the key
+// like [GetUintSlice], and reports the elements that could not be parsed with a [*ParseError]
+// instead of returning a default value, see [LookupStringSlice].
+func LookupUintSlice[V Unsigned](key, separator string, base int, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(currentSource(), key, separator, uintParser[V](base), opts)
+}
+
+// LookupDurationSlice returns the []time.Duration values of the environment variable named by
+// the key like [GetDurationSlice], and reports the elements that could not be parsed with a
+// [*ParseError] instead of returning a default value, see [LookupStringSlice].
+func LookupDurationSlice(key, separator string, opts ...SliceOption) ([]time.Duration, error) {
+	return lookupSlice(currentSource(), key, separator, time.ParseDuration, opts)
+}
+
+// LookupURLSlice returns the [][net/url.URL] values of the environment variable named by the
+// key like [GetURLSlice], and reports the elements that could not be parsed with a
+// [*ParseError] instead of returning a default value, see [LookupStringSlice].
+func LookupURLSlice(key, separator string, opts ...SliceOption) ([]url.URL, error) {
+	return lookupSlice(currentSource(), key, separator, parseURL, opts)
+}
+
+// GetStringMap returns the map of [String] values of the environment variables named by the
+// prefix followed by the separator and the key of an entry, e.g. LIMIT_USERS for the entry
+// USERS of the prefix LIMIT and the separator "_". Variables whose key contains the separator,
+// which belong to deeper levels of the hierarchy, are ignored. The defaultValue is returned
+// only if there is no such variable.
+func GetStringMap[V String](prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(currentSource(), prefix, separator, defaultValue, parseString[V])
+}
+
+// GetBoolMap returns the map of [Boolean] values of the environment variables named by the
+// prefix followed by the separator and the key of an entry, see [GetStringMap]. The
+// defaultValue is returned only if there is no such variable or any of the associated values
+// could not be parsed. Refer to [strconv.ParseBool] for supported values.
+func GetBoolMap[V Boolean](prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(currentSource(), prefix, separator, defaultValue, parseBool[V])
+}
+
+// GetIntMap returns the map of [Signed] values of the environment variables named by the
+// prefix followed by the separator and the key of an entry, see [GetStringMap]. The
+// defaultValue is returned only if there is no such variable or any of the associated values
+// could not be parsed. Refer to [strconv.ParseInt] for supported values.
+func GetIntMap[V Signed](prefix, separator string, base int, defaultValue map[string]V) map[string]V {
+	return getMap(currentSource(), prefix, separator, defaultValue, intParser[V](base))
+}
+
+// GetUintMap returns the map of [Unsigned] values of the environment variables named by the
+// prefix followed by the separator and the key of an entry, see [GetStringMap]. The
+// defaultValue is returned only if there is no such variable or any of the associated values
+// could not be parsed. Refer to [strconv.ParseUint] for supported values.
+func GetUintMap[V Unsigned](prefix, separator string, base int, defaultValue map[string]V) map[string]V {
+	return getMap(currentSource(), prefix, separator, defaultValue, uintParser[V](base))
+}
+
+// GetFloatMap returns the map of [Float] values of the environment variables named by the
+// prefix followed by the separator and the key of an entry, see [GetStringMap]. The
+// defaultValue is returned only if there is no such variable or any of the associated values
+// could not be parsed. Refer to [strconv.ParseFloat] for supported values.
+func GetFloatMap[V Float](prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(currentSource(), prefix, separator, defaultValue, parseFloat[V])
+}
+
+// GetDurationMap returns the map of time.Duration values of the environment variables named by
+// the prefix followed by the separator and the key of an entry, see [GetStringMap]. The
+// defaultValue is returned only if there is no such variable or any of the associated values
+// could not be parsed. Refer to [time.ParseDuration] for supported values.
+func GetDurationMap(prefix, separator string, defaultValue map[string]time.Duration) map[string]time.Duration {
+	return getMap(currentSource(), prefix, separator, defaultValue, time.ParseDuration)
+}
+
+// GetURLMap returns the map of [net/url.URL] values of the environment variables named by the
+// prefix followed by the separator and the key of an entry, see [GetStringMap]. The
+// defaultValue is returned only if there is no such variable or any of the associated values
+// could not be parsed. Refer to [net/url.ParseRequestURI] for supported values.
+func GetURLMap(prefix, separator string, defaultValue map[string]url.URL) map[string]url.URL {
+	return getMap(currentSource(), prefix, separator, defaultValue, parseURL)
+}
+
+// MaybeString returns the [String] value of the environment variable named by the key as an
+// [Optional], which is not set if the variable is not present. Unlike [GetString], it tells a
+// variable that is not present apart from one set to the zero value.
+func MaybeString[V String](key string) Optional[V] {
+	return maybe(currentSource(), key, parseString[V])
+}
+
+// MaybeBool returns the [Boolean] value of the environment variable named by the key as an
+// [Optional], which is not set if the variable is not present or holds the [*ParseError] if
+// the associated value could not be parsed. Refer to [strconv.ParseBool] for supported values.
+func MaybeBool[V Boolean](key string) Optional[V] {
+	return maybe(currentSource(), key, parseBool[V])
+}
+
+// MaybeInt returns the [Signed] value of the environment variable named by the key as an
+// [Optional], which is not set if the variable is not present or holds the [*ParseError] if
+// the associated value could not be parsed. Refer to [strconv.ParseInt] for supported values.
+func MaybeInt[V Signed](key string, base int) Optional[V] {
+	return maybe(currentSource(), key, intParser[V](base))
+}
+
+// MaybeUint returns the [Unsigned] value of the environment variable named by the key as an
+// [Optional], which is not set if the variable is not present or holds the [*ParseError] if
+// the associated value could not be parsed. Refer to [strconv.ParseUint] for supported values.
+func MaybeUint[V Unsigned](key string, base int) Optional[V] {
+	return maybe(currentSource(), key, uintParser[V](base))
+}
+
+// MaybeFloat returns the [Float] value of the environment variable named by the key as an
+// [Optional], which is not set if the variable is not present or holds the [*ParseError] if
+// the associated value could not be parsed. Refer to [strconv.ParseFloat] for supported
+// values.
+func MaybeFloat[V Float](key string) Optional[V] {
+	return maybe(currentSource(), key, parseFloat[V])
+}
+
+// MaybeDuration returns the time.Duration value of the environment variable named by the key
+// as an [Optional], which is not set if the variable is not present or holds the [*ParseError]
+// if the associated value could not be parsed. Refer to [time.ParseDuration] for supported
+// values.
+func MaybeDuration(key string) Optional[time.Duration] {
+	return maybe(currentSource(), key, time.ParseDuration)
+}
+
+// MaybeURL returns the [net/url.URL] value of the environment variable named by the key as an
+// [Optional], which is not set if the variable is not present or holds the [*ParseError] if
+// the associated value could not be parsed. Refer to [net/url.ParseRequestURI] for supported
+// values.
+func MaybeURL(key string) Optional[url.URL] {
+	return maybe(currentSource(), key, parseURL)
+}
+
+// MaybeStringSlice returns the [String] values of the environment variable named by the key as
+// an [Optional], see [MaybeString] and [GetStringSlice].
+func MaybeStringSlice[V String](key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(currentSource(), key, separator, parseString[V], opts)
+}
+
+// MaybeBoolSlice returns the [Boolean] values of the environment variable named by the key as
+// an [Optional], see [MaybeBool] and [GetBoolSlice].
+func MaybeBoolSlice[V Boolean](key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(currentSource(), key, separator, parseBool[V], opts)
+}
+
+// MaybeIntSlice returns the [Signed] values of the environment variable named by the key as an
+// [Optional], see [MaybeInt] and [GetIntSlice].
+func MaybeIntSlice[V Signed](key, separator string, base int, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(currentSource(), key, separator, intParser[V](base), opts)
+}
+
+// MaybeUintSlice returns the [Unsigned] values of the environment variable named by the key as
+// an [Optional], see [MaybeUint] and [GetUintSlice].
+func MaybeUintSlice[V Unsigned](key, separator string, base int, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(currentSource(), key, separator, uintParser[V](base), opts)
+}
+
+// MaybeFloatSlice returns the [Float] values of the environment variable named by the key as
+// an [Optional], see [MaybeFloat].
+func MaybeFloatSlice[V Float](key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(currentSource(), key, separator, parseFloat[V], opts)
+}
+
+// MaybeDurationSlice returns the time.Duration values of the environment variable named by the
+// key as an [Optional], see [MaybeDuration] and [GetDurationSlice].
+func MaybeDurationSlice(key, separator string, opts ...SliceOption) Optional[[]time.Duration] {
+	return maybeSlice(currentSource(), key, separator, time.ParseDuration, opts)
+}
+
+// MaybeURLSlice returns the [net/url.URL] values of the environment variable named by the key
+// as an [Optional], see [MaybeURL] and [GetURLSlice].
+func MaybeURLSlice(key, separator string, opts ...SliceOption) Optional[[]url.URL] {
+	return maybeSlice(currentSource(), key, separator, parseURL, opts)
+}
+
+// get resolves the environment variable named by the key through the package [Source],
+// see [SetSource].
+func get[V any](key string, defaultValue V, parse func(string) (V, error)) V {
+	if packageSource.Load() == nil {
+		return getFromOS(key, defaultValue, parse)
+	}
+
+	return getFrom(currentSource(), key, defaultValue, parse)
+}
+
+// getSlice resolves the slice named by the key through src, splitting it by the separator and
+// parsing every element with parse as configured by opts.
+func getSlice[V any](src Source, key, separator string, defaultValue []V, parse func(string) (V, error), opts []SliceOption) []V {
+	src, parseSlice := sliceLookup(src, separator, parse, opts)
+
+	return getFrom(src, key, defaultValue, parseSlice)
+}
+
+// sliceLookup returns the source and the parser resolving a slice through src, see
+// [bindSlice], splitting values by the separator and parsing every element with parse as
+// configured by opts.
+func sliceLookup[V any](src Source, separator string, parse func(string) (V, error), opts []SliceOption) (Source, func(string) ([]V, error)) {
+	o := newSliceOptions(opts)
+
+	return bindSlice(o, src, separator, sliceParser(separator, parse, opts...), elementsParser(parse, o))
+}
+
+// getFrom resolves the environment variable named by the key through src. The defaultValue
+// is returned if the variable is not present or parse fails.
+func getFrom[V any](src Source, key string, defaultValue V, parse func(string) (V, error)) V {
+	if m, ok := src.(*Map); ok {
+		if val, ok := parseFromMap(m, key, defaultValue, parse); ok {
+			return val
+		}
+	}
+
+	val, _, _ := lookupValue(src, key, defaultValue, parse)
+
+	return val
+}
+
+// lookupSlice resolves the slice named by the key through src, splitting it by the separator
+// and parsing every element with parse as configured by opts. A nil slice is returned if the
+// variable is not present or its value could not be parsed.
+func lookupSlice[V any](src Source, key, separator string, parse func(string) (V, error), opts []SliceOption) ([]V, error) {
+	src, parseSlice := sliceLookup(src, separator, parse, opts)
+	val, _, err := lookupValue(src, key, nil, parseSlice)
+
+	return val, err
+}
+
+// lookupValue resolves the environment variable named by the key through src and records
+// the provenance of the returned value. The boolean reports whether the variable is present.
+// The defaultValue is returned if the variable is not present, or together with a
+// [*ParseError] if parse fails. If invalid elements of a slice were skipped, the valid ones
+// are returned together with the [*ParseError]. Empty values are handled following the
+// [EmptyPolicy].
+func lookupValue[V any](src Source, key string, defaultValue V, parse func(string) (V, error)) (V, bool, error) {
+	val, resolved, ok := lookupFrom(src, key)
+
+	policy := currentEmptyPolicy()
+	if ok && val == "" && policy == EmptyAsUnset {
+		ok = false
+	}
+
 	if !ok {
-		return defaultValue
+		return missingValue(src, key, defaultValue), false, nil
 	}
 
-	stringVals := strings.Split(val, separator)
+	var (
+		parsed V
+		err    error
+	)
 
-	boolSlice := make([]V, 0, len(stringVals))
+	switch {
+	case val == "" && policy == EmptyAsError:
+		err = ErrEmpty
+	case val == "" && policy == EmptyAsZero:
+		parsed = zeroLike(defaultValue)
+	default:
+		parsed, err = parse(val)
+	}
 
-	for _, boolStrVal := range stringVals {
-		parsedBool, err := strconv.ParseBool(boolStrVal)
-		if err != nil {
-			return defaultValue
+	if err != nil {
+		var skipped *skippedError
+		if !errors.As(err, &skipped) {
+			p := Provenance{Key: key, Source: ProvenanceDefaultAfterParseError}
+			if !overlaid(src, resolved) {
+				recordProvenance(p)
+			}
+			logParseError(key, val, err)
+			logRead(key, defaultValue, p, true)
+
+			parseErr := &ParseError{Key: resolved, Value: val, Err: err}
+			if o, ok := src.(lookupObserver); ok {
+				o.observeParseError(parseErr)
+			}
+
+			return defaultValue, true, parseErr
 		}
 
-		boolSlice = append(boolSlice, V(parsedBool))
+		err = skipped.err
+	}
+
+	if observingReads() {
+		p := provenanceOf(src, resolved)
+		p.Key = key
+		if !overlaid(src, resolved) {
+			recordProvenance(p)
+		}
+		logRead(key, val, p, false)
 	}
 
-	return boolSlice
+	if err != nil {
+		// The invalid elements of the slice were skipped, so the valid ones are returned and
+		// the errors of the others reported.
+		logSkippedElements(key, val, err)
+
+		parseErr := &ParseError{Key: resolved, Value: val, Err: err}
+		if o, ok := src.(lookupObserver); ok {
+			o.observeParseError(parseErr)
+		}
+
+		return parsed, true, parseErr
+	}
+
+	return parsed, true, nil
 }
 
-// GetIntSlice returns the associated [][Signed] values for the provided environment
-// variable named by the key. The defaultValue is returned only if the environment variable
-// is not present or any of the associated values could not be parsed. Refer to [strconv.ParseInt]
-// for supported values.
-func GetIntSlice[V Signed](key, separator string, base int, defaultValue []V) []V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
+// missingValue reports the variable named by the key as missing from src and records the
+// provenance of the defaultValue it resolves to.
+func missingValue[V any](src Source, key string, defaultValue V) V {
+	if o, ok := src.(lookupObserver); ok {
+		o.observeMissing(key)
 	}
 
-	stringVals := strings.Split(val, separator)
-	intSlice := make([]V, 0, len(stringVals))
+	if observingReads() {
+		p := Provenance{Key: key, Source: ProvenanceDefault}
+		recordProvenance(p)
+		logRead(key, defaultValue, p, true)
+	}
+
+	return defaultValue
+}
 
-	var parser func(string) (int64, error)
+// ParseError is returned when the value of an environment variable could not be parsed.
+type ParseError struct {
+	// Key is the name of the environment variable the value was read from.
+	Key string
+	// Value is the raw value that could not be parsed.
+	Value string
+	// Err is the error returned by the parser.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("env: invalid value for %s: %s", e.Key, e.Err.Error())
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// lookupFrom retrieves the raw value of the environment variable named by the key, or of
+// the first of its aliases present, from src and records the keys as read, see [UnknownKeys].
+// The returned key is the one the value was read from.
+func lookupFrom(src Source, key string) (string, string, bool) {
+	if tracking.Load() && !overlaid(src, key) {
+		recordRead(key)
+	}
+
+	val, ok := src.Lookup(key)
+
+	return resolveAliases(src, key, val, ok)
+}
+
+func parseString[V String](val string) (V, error) {
+	return V(val), nil
+}
+
+func parseBool[V Boolean](val string) (V, error) {
+	parsedBool, err := strconv.ParseBool(val)
+
+	return V(parsedBool), err
+}
+
+func intParser[V Signed](base int) func(string) (V, error) {
+	var bitSize int
 
 	var h V
 	switch any(h).(type) {
 	case int:
-		parser = func(val string) (int64, error) {
-			return strconv.ParseInt(val, base, 0)
-		}
+		bitSize = 0
 	case int8:
-		parser = func(val string) (int64, error) {
-			return strconv.ParseInt(val, base, 8)
-		}
+		bitSize = 8
 	case int16:
-		parser = func(val string) (int64, error) {
-			return strconv.ParseInt(val, base, 16)
-		}
+		bitSize = 16
 	case int32:
-		parser = func(val string) (int64, error) {
-			return strconv.ParseInt(val, base, 32)
-		}
-	case int64:
-		parser = func(val string) (int64, error) {
-			return strconv.ParseInt(val, base, 64)
-		}
+		bitSize = 32
 	default:
-		parser = func(val string) (int64, error) {
-			return strconv.ParseInt(val, base, 64)
-		}
+		bitSize = 64
 	}
 
-	for _, intStrVal := range stringVals {
-		parsedInt, err := parser(intStrVal)
-		if err != nil {
-			return defaultValue
-		}
+	return func(val string) (V, error) {
+		parsedInt, err := strconv.ParseInt(val, base, bitSize)
 
-		intSlice = append(intSlice, V(parsedInt))
+		return V(parsedInt), err
 	}
-
-	return intSlice
 }
 
-// GetUintSlice returns the associated [][Unsigned] values for the provided environment
-// variable named by the key. The defaultValue is returned only if the environment variable
-// is not present or any of the associated values could not be parsed. Refer to [strconv.ParseUint]
-// for supported values.
-func GetUintSlice[V Unsigned](key, separator string, base int, defaultValue []V) []V {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
-
-	stringVals := strings.Split(val, separator)
-	uintSlice := make([]V, 0, len(stringVals))
-
-	var parser func(string) (uint64, error)
+func uintParser[V Unsigned](base int) func(string) (V, error) {
+	var bitSize int
 
 	var h V
 	switch any(h).(type) {
 	case uint:
-		parser = func(val string) (uint64, error) {
-			return strconv.ParseUint(val, base, 0)
-		}
+		bitSize = 0
 	case uint8:
-		parser = func(val string) (uint64, error) {
-			return strconv.ParseUint(val, base, 8)
-		}
+		bitSize = 8
 	case uint16:
-		parser = func(val string) (uint64, error) {
-			return strconv.ParseUint(val, base, 16)
-		}
+		bitSize = 16
 	case uint32:
-		parser = func(val string) (uint64, error) {
-			return strconv.ParseUint(val, base, 32)
-		}
-	case uint64:
-		parser = func(val string) (uint64, error) {
-			return strconv.ParseUint(val, base, 64)
-		}
+		bitSize = 32
 	default:
-		parser = func(val string) (uint64, error) {
-			return strconv.ParseUint(val, base, 64)
-		}
+		bitSize = 64
 	}
 
-	for _, uintStrVal := range stringVals {
-		parsedInt, err := parser(uintStrVal)
-		if err != nil {
-			return defaultValue
-		}
+	return func(val string) (V, error) {
+		parsedUint, err := strconv.ParseUint(val, base, bitSize)
 
-		uintSlice = append(uintSlice, V(parsedInt))
+		return V(parsedUint), err
 	}
-
-	return uintSlice
 }
 
-// GetDurationSlice returns the associated []time.Duration values for the provided environment
-// variable named by the key. The defaultValue is returned only if the environment variables
-// is not present or any of the associated values could not be parsed. Refer to [time.ParseDuration]
-// for supported values.
-func GetDurationSlice(key, separator string, defaultValue []time.Duration) []time.Duration {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
+func parseFloat[V Float](val string) (V, error) {
+	bitSize := 64
+
+	var h V
+	if _, ok := any(h).(float32); ok {
+		bitSize = 32
 	}
 
-	stringVals := strings.Split(val, separator)
+	parsedFloat, err := strconv.ParseFloat(val, bitSize)
 
-	durationSlice := make([]time.Duration, 0, len(stringVals))
+	return V(parsedFloat), err
+}
 
-	for _, durationStrVal := range stringVals {
-		parsedDuration, err := time.ParseDuration(durationStrVal)
+func parseURL(val string) (url.URL, error) {
+	parsedURL, err := url.ParseRequestURI(val)
+	if err != nil {
+		return url.URL{}, err
+	}
+
+	return *parsedURL, nil
+}
+
+// sliceParser returns a parser splitting a value by the separator as configured by opts and
+// parsing every element with parse. Parsing fails if the value could not be split or any of
+// the elements could not be parsed, with an [*ElementError] for every such element. Invalid
+// elements skipped with [SliceSkipInvalid] are reported with a [*skippedError] returned
+// along with the valid elements.
+func sliceParser[V any](separator string, parse func(string) (V, error), opts ...SliceOption) func(string) ([]V, error) {
+	o := newSliceOptions(opts)
+	parseElems := elementsParser(parse, o)
+
+	return func(val string) ([]V, error) {
+		stringVals, err := splitSlice(val, separator, o)
 		if err != nil {
-			return defaultValue
+			return nil, err
 		}
 
-		durationSlice = append(durationSlice, parsedDuration)
+		return parseElems(stringVals)
 	}
-
-	return durationSlice
 }
 
-// GetURLSlice returns the associated [net/url.URL] values for the provided environment
-// variable named by the key. The defaultValue is returned only if the environment variables
-// is not present or any of the associated values could not be parsed. Refer to [net/url.ParseRequestURI]
-// for supported values.
-func GetURLSlice(key, separator string, defaultValue []url.URL) []url.URL {
-	val, ok := os.LookupEnv(key)
-	if !ok {
-		return defaultValue
-	}
+// elementsParser returns a parser parsing every element of a slice with parse as configured
+// by o.
+func elementsParser[V any](parse func(string) (V, error), o sliceOptions) func([]string) ([]V, error) {
+	return func(stringVals []string) ([]V, error) {
+		slice := make([]V, 0, len(stringVals))
 
-	stringVals := strings.Split(val, separator)
+		var errs []error
 
-	urlSlice := make([]url.URL, 0, len(stringVals))
+		for i, strVal := range stringVals {
+			parsed, err := parse(strVal)
+			if err != nil {
+				errs = append(errs, &ElementError{Index: o.elementIndex(i), Value: strVal, Err: err})
+				continue
+			}
 
-	for _, urlStrVal := range stringVals {
-		parsedDuration, err := url.ParseRequestURI(urlStrVal)
-		if err != nil {
-			return defaultValue
+			slice = append(slice, parsed)
 		}
 
-		urlSlice = append(urlSlice, *parsedDuration)
+		return slice, o.elementsError(errs)
 	}
-
-	return urlSlice
 }
@@ -953,7 +953,41 @@ func TestGetURLSlice(t *testing.T) {
 	}
 }
 
+func TestGetUntracked(t *testing.T) {
+	untracked(t)
+
+	t.Setenv("KEY_UNTRACKED_INT", "3")
+	if val := GetInt("KEY_UNTRACKED_INT", 10, 0); val != 3 {
+		t.Errorf("expected env var %s value %d got %d", "KEY_UNTRACKED_INT", 3, val)
+	}
+
+	t.Setenv("KEY_UNTRACKED_INT", "invalid")
+	if val := GetInt("KEY_UNTRACKED_INT", 10, 1); val != 1 {
+		t.Errorf("expected default value %d got %d", 1, val)
+	}
+
+	setEmptyPolicy(t, EmptyAsZero)
+
+	t.Setenv("KEY_UNTRACKED_INT", "")
+	if val := GetInt("KEY_UNTRACKED_INT", 10, 1); val != 0 {
+		t.Errorf("expected zero value got %d", val)
+	}
+
+	if _, ok := provenances.Load("KEY_UNTRACKED_INT"); ok {
+		t.Errorf("expected the provenance of KEY_UNTRACKED_INT not to be recorded")
+	}
+}
+
+// untracked disables tracking for the duration of the test, which [TestMain] enables, so that
+// the getters run as configured by default.
+func untracked(tb testing.TB) {
+	SetTracking(false)
+	tb.Cleanup(func() { SetTracking(true) })
+}
+
 func BenchmarkGetString(b *testing.B) {
+	untracked(b)
+
 	defaultVal := "default"
 	envKey := "KEY_STRING"
 
@@ -964,3 +998,64 @@ func BenchmarkGetString(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkGetStringFromSnapshot(b *testing.B) {
+	untracked(b)
+
+	defaultVal := "default"
+	envKey := "KEY_STRING"
+	snapshot := Snapshot()
+
+	for i := 0; i < b.N; i++ {
+		val := GetStringFrom(snapshot, envKey, defaultVal)
+		if val != defaultVal {
+			b.Errorf("expected default value %s got %s", defaultVal, val)
+		}
+	}
+}
+
+func BenchmarkGetInt(b *testing.B) {
+	untracked(b)
+
+	envKey := "KEY_INT"
+	b.Setenv(envKey, "3")
+
+	for i := 0; i < b.N; i++ {
+		val := GetInt(envKey, 10, 0)
+		if val != 3 {
+			b.Errorf("expected env var %s value %d got %d", envKey, 3, val)
+		}
+	}
+}
+
+func BenchmarkGetIntFromSnapshot(b *testing.B) {
+	untracked(b)
+
+	envKey := "KEY_INT"
+	b.Setenv(envKey, "3")
+	snapshot := Snapshot()
+
+	for i := 0; i < b.N; i++ {
+		val := GetIntFrom(snapshot, envKey, 10, 0)
+		if val != 3 {
+			b.Errorf("expected env var %s value %d got %d", envKey, 3, val)
+		}
+	}
+}
+
+func BenchmarkGetIntFromSnapshotParallel(b *testing.B) {
+	untracked(b)
+
+	envKey := "KEY_INT"
+	b.Setenv(envKey, "3")
+	snapshot := Snapshot()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			val := GetIntFrom(snapshot, envKey, 10, 0)
+			if val != 3 {
+				b.Errorf("expected env var %s value %d got %d", envKey, 3, val)
+			}
+		}
+	})
+}
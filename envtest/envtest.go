@@ -0,0 +1,166 @@
+// Package envtest provides helpers for testing code that reads environment variables without
+// modifying the process environment, so tests using it can run in parallel.
+package envtest
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/rojbar/env/v2"
+)
+
+// Provenance is recorded for values read from a [Source].
+const Provenance = "envtest"
+
+// Source is a fake [env.Source] backed by a map. Unlike [env.Map] its values can be changed
+// with [Source.Set] and [Source.Unset], for example to test code that reloads its
+// configuration. It is safe for concurrent use.
+type Source struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewSource returns a [Source] holding a copy of the values.
+func NewSource(values map[string]string) *Source {
+	s := &Source{values: make(map[string]string, len(values))}
+	for key, val := range values {
+		s.values[key] = val
+	}
+
+	return s
+}
+
+// Set sets the value of the variable named by the key.
+func (s *Source) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+}
+
+// Unset removes the variable named by the key.
+func (s *Source) Unset(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+}
+
+// Lookup retrieves the value of the variable named by the key.
+func (s *Source) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.values[key]
+
+	return val, ok
+}
+
+// Keys returns the keys of all variables in lexicographical order.
+func (s *Source) Keys() []string {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Provenance returns the provenance of the variable named by the key.
+func (s *Source) Provenance(key string) env.Provenance {
+	return env.Provenance{Key: key, Source: Provenance}
+}
+
+// With returns a [Source] holding the values, scoped to the test: it is only visible to code
+// it is passed to, e.g. through the *From getters, [env.Var.Lookup] or [env.UnmarshalFrom], so
+// unlike t.Setenv it can be used by parallel tests and subtests. The values are removed once
+// the test and its subtests complete, so code still reading through the source afterwards,
+// such as a goroutine outliving the test, does not see them.
+func With(t testing.TB, values map[string]string) *Source {
+	t.Helper()
+
+	s := NewSource(values)
+	t.Cleanup(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.values = make(map[string]string)
+	})
+
+	return s
+}
+
+// Use replaces the package source of env with src for the duration of the test, for code
+// that reads the package source through the getters, and restores the previous source once
+// the test completes. Like t.Setenv it affects the whole process, so it must not be used in
+// parallel tests.
+func Use(t testing.TB, src env.Source) {
+	t.Helper()
+
+	prev := env.CurrentSource()
+
+	env.SetSource(src)
+	t.Cleanup(func() {
+		env.SetSource(prev)
+	})
+}
+
+// LoadDotenv loads the dotenv file named by the path, typically a fixture in testdata, with
+// [env.LoadDotenv] and fails the test if it can not be loaded.
+func LoadDotenv(t testing.TB, path string) *env.Dotenv {
+	t.Helper()
+
+	d, err := env.LoadDotenv(path)
+	if err != nil {
+		t.Fatalf("load dotenv fixture failed %s", err.Error())
+	}
+
+	return d
+}
+
+// AssertValue fails the test unless lookup, typically the Lookup method of an [env.Var],
+// resolves the expected value through src without error.
+func AssertValue[T any](t testing.TB, src env.Source, lookup func(env.Source) (T, error), expected T) {
+	t.Helper()
+
+	val, err := lookup(src)
+	if err != nil {
+		t.Errorf("expected %v got error %s", expected, err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(val, expected) {
+		t.Errorf("expected %v got %v", expected, val)
+	}
+}
+
+// AssertParseError fails the test unless lookup, typically the Lookup method of an
+// [env.Var], returns an [*env.ParseError] for the variable named by the key when resolving
+// through src. If target is not nil the error must also match it with [errors.Is], e.g.
+// [strconv.ErrRange].
+func AssertParseError[T any](t testing.TB, src env.Source, lookup func(env.Source) (T, error), key string, target error) {
+	t.Helper()
+
+	_, err := lookup(src)
+
+	var parseErr *env.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expected parse error for %s got %v", key, err)
+		return
+	}
+
+	if parseErr.Key != key {
+		t.Errorf("expected parse error for %s got %s", key, parseErr.Key)
+	}
+
+	if target != nil && !errors.Is(err, target) {
+		t.Errorf("expected parse error matching %v got %v", target, err)
+	}
+}
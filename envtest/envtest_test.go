@@ -0,0 +1,131 @@
+package envtest
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rojbar/env/v2"
+)
+
+var (
+	port    = env.IntVar("ENVTEST_PORT", 10, 8080, "listen port")
+	timeout = env.DurationVar("ENVTEST_TIMEOUT", time.Second, "request timeout")
+)
+
+// recorder records the failures reported by the assertion helpers.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{"1", 1},
+		{"2", 2},
+		{"3", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			t.Parallel()
+
+			src := With(t, map[string]string{"ENVTEST_PORT": test.value})
+
+			AssertValue(t, src, port.Lookup, test.expected)
+
+			if val := env.GetIntFrom(src, "ENVTEST_PORT", 10, 0); val != test.expected {
+				t.Errorf("expected %d got %d", test.expected, val)
+			}
+		})
+	}
+}
+
+func TestSource(t *testing.T) {
+	src := NewSource(map[string]string{"B": "2", "A": "1"})
+
+	src.Set("C", "3")
+	src.Unset("A")
+
+	keys := src.Keys()
+	if len(keys) != 2 || keys[0] != "B" || keys[1] != "C" {
+		t.Errorf("expected keys [B C] got %v", keys)
+	}
+
+	if p := src.Provenance("B"); p.Source != Provenance {
+		t.Errorf("expected provenance %s got %s", Provenance, p.Source)
+	}
+}
+
+func TestUse(t *testing.T) {
+	Use(t, With(t, map[string]string{"ENVTEST_PORT": "1234"}))
+
+	t.Run("nested", func(t *testing.T) {
+		Use(t, With(t, map[string]string{"ENVTEST_PORT": "5678"}))
+
+		if val := port.Get(); val != 5678 {
+			t.Errorf("expected 5678 got %d", val)
+		}
+	})
+
+	if val := port.Get(); val != 1234 {
+		t.Errorf("expected previous source to be restored got %d", val)
+	}
+}
+
+func TestWithCleanup(t *testing.T) {
+	var src *Source
+
+	t.Run("scoped", func(t *testing.T) {
+		src = With(t, map[string]string{"ENVTEST_PORT": "1234"})
+
+		if _, ok := src.Lookup("ENVTEST_PORT"); !ok {
+			t.Errorf("expected value to be present")
+		}
+	})
+
+	if val, ok := src.Lookup("ENVTEST_PORT"); ok {
+		t.Errorf("expected value to be removed after the test got %s", val)
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Parallel()
+
+	src := LoadDotenv(t, "testdata/app.env")
+
+	AssertValue(t, src, port.Lookup, 9090)
+	AssertValue(t, src, timeout.Lookup, time.Minute)
+}
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+
+	src := With(t, map[string]string{"ENVTEST_PORT": "99999999999999999999", "ENVTEST_TIMEOUT": "soon"})
+
+	AssertParseError(t, src, port.Lookup, "ENVTEST_PORT", strconv.ErrRange)
+	AssertParseError(t, src, timeout.Lookup, "ENVTEST_TIMEOUT", nil)
+
+	r := &recorder{TB: t}
+
+	AssertValue(r, src, port.Lookup, 8080)
+	AssertValue(r, With(t, nil), port.Lookup, 80)
+	AssertParseError(r, src, port.Lookup, "ENVTEST_PORT", strconv.ErrSyntax)
+	AssertParseError(r, With(t, nil), port.Lookup, "ENVTEST_PORT", nil)
+
+	if len(r.failures) != 4 {
+		t.Errorf("expected 4 failures got %d: %q", len(r.failures), r.failures)
+	}
+}
@@ -0,0 +1,189 @@
+package env
+
+import (
+	"errors"
+	"flag"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FlagKey returns the key of the environment variable backing the flag named by the name,
+// the name converted with [UpperSnakeKey] and prefixed with the prefix, e.g. the flag
+// http-addr with the prefix APP_ is backed by APP_HTTP_ADDR.
+func FlagKey(prefix, name string) string {
+	return prefix + UpperSnakeKey(name)
+}
+
+// Flags records which flags of a [flag.FlagSet] were passed explicitly on the command line,
+// see [PassedFlags].
+type Flags struct {
+	fs     *flag.FlagSet
+	passed map[string]struct{}
+}
+
+// PassedFlags records the flags of fs passed explicitly on the command line. It must be called
+// after fs.Parse and before [SetFlags], since setting a flag from the environment marks it as
+// set in fs, which could then no longer tell it apart from a flag passed explicitly.
+func PassedFlags(fs *flag.FlagSet) Flags {
+	passed := make(map[string]struct{})
+	fs.Visit(func(f *flag.Flag) {
+		passed[f.Name] = struct{}{}
+	})
+
+	return Flags{fs: fs, passed: passed}
+}
+
+// SetFlags sets every flag that was not passed explicitly on the command line, as recorded by
+// [PassedFlags], from the environment variable named by [FlagKey], if present in the package
+// [Source]. Explicit flags thus override the environment, which overrides the flag defaults.
+// The returned error joins a [*ParseError] for every value rejected by a flag.
+func SetFlags(flags Flags, prefix string) error {
+	return SetFlagsFrom(currentSource(), flags, prefix)
+}
+
+// SetFlagsFrom is like [SetFlags] but reads the environment variables from src instead of the
+// package [Source].
+func SetFlagsFrom(src Source, flags Flags, prefix string) error {
+	var errs []error
+
+	flags.fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := flags.passed[f.Name]; ok {
+			return
+		}
+
+		key := FlagKey(prefix, f.Name)
+
+		val, resolved, ok := lookupFrom(src, key)
+		if !ok {
+			return
+		}
+
+		if err := flags.fs.Set(f.Name, val); err != nil {
+			errs = append(errs, &ParseError{Key: resolved, Value: val, Err: err})
+			return
+		}
+
+		p := provenanceOf(src, resolved)
+		p.Key = key
+		recordProvenance(p)
+	})
+
+	return errors.Join(errs...)
+}
+
+// ProvenanceFlag is recorded for values read from the flags passed on the command line.
+const ProvenanceFlag = "flag"
+
+// FlagSource returns a [Source] holding the flags passed explicitly on the command line, as
+// recorded by [PassedFlags], keyed by [FlagKey], so flags can be stacked with other sources in
+// a [Layered] source. Flags set from the environment by [SetFlags] are not included, whether
+// SetFlags is called before or after FlagSource.
+func FlagSource(flags Flags, prefix string) Source {
+	values := make(map[string]string)
+	for name := range flags.passed {
+		values[FlagKey(prefix, name)] = flags.fs.Lookup(name).Value.String()
+	}
+
+	return newMap(values, ProvenanceFlag)
+}
+
+// flagValue is a [flag.Value] parsing its value with the same rules as the getters.
+type flagValue[T any] struct {
+	p      *T
+	parse  func(string) (T, error)
+	format func(T) string
+}
+
+func (v *flagValue[T]) Set(s string) error {
+	parsed, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+
+	*v.p = parsed
+
+	return nil
+}
+
+func (v *flagValue[T]) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return v.format(*v.p)
+}
+
+// IntFlag returns a [flag.Value] storing into p values parsed like [GetInt]. It
+// panics if base is not 0 or between 2 and 36.
+func IntFlag[V Signed](p *V, base int) flag.Value {
+	checkBase(base, "IntFlag")
+
+	return &flagValue[V]{p: p, parse: intParser[V](base), format: func(v V) string { return formatInt(v, base) }}
+}
+
+// UintFlag returns a [flag.Value] storing into p values parsed like [GetUint]. It
+// panics if base is not 0 or between 2 and 36.
+func UintFlag[V Unsigned](p *V, base int) flag.Value {
+	checkBase(base, "UintFlag")
+
+	return &flagValue[V]{p: p, parse: uintParser[V](base), format: func(v V) string { return formatUint(v, base) }}
+}
+
+// FloatFlag returns a [flag.Value] storing into p values parsed like [GetFloat].
+func FloatFlag[V Float](p *V) flag.Value {
+	return &flagValue[V]{p: p, parse: parseFloat[V], format: formatFloat[V]}
+}
+
+// URLFlag returns a [flag.Value] storing into p values parsed like [GetURL].
+func URLFlag(p *url.URL) flag.Value {
+	return &flagValue[url.URL]{p: p, parse: parseURL, format: formatURL}
+}
+
+// StringSliceFlag returns a [flag.Value] storing into p values parsed like [GetStringSlice].
+func StringSliceFlag[V String](p *[]V, separator string, opts ...SliceOption) flag.Value {
+	return &flagValue[[]V]{p: p, parse: sliceParser(separator, parseString[V], opts...), format: func(v []V) string {
+		return formatSlice(v, separator, func(s V) string { return string(s) }, opts...)
+	}}
+}
+
+// BoolSliceFlag returns a [flag.Value] storing into p values parsed like [GetBoolSlice].
+func BoolSliceFlag[V Boolean](p *[]V, separator string, opts ...SliceOption) flag.Value {
+	return &flagValue[[]V]{p: p, parse: sliceParser(separator, parseBool[V], opts...), format: func(v []V) string {
+		return formatSlice(v, separator, func(b V) string { return strconv.FormatBool(bool(b)) }, opts...)
+	}}
+}
+
+// IntSliceFlag returns a [flag.Value] storing into p values parsed like [GetIntSlice]. It
+// panics if base is not 0 or between 2 and 36.
+func IntSliceFlag[V Signed](p *[]V, separator string, base int, opts ...SliceOption) flag.Value {
+	checkBase(base, "IntSliceFlag")
+
+	return &flagValue[[]V]{p: p, parse: sliceParser(separator, intParser[V](base), opts...), format: func(v []V) string {
+		return formatSlice(v, separator, func(i V) string { return formatInt(i, base) }, opts...)
+	}}
+}
+
+// UintSliceFlag returns a [flag.Value] storing into p values parsed like [GetUintSlice]. It
+// panics if base is not 0 or between 2 and 36.
+func UintSliceFlag[V Unsigned](p *[]V, separator string, base int, opts ...SliceOption) flag.Value {
+	checkBase(base, "UintSliceFlag")
+
+	return &flagValue[[]V]{p: p, parse: sliceParser(separator, uintParser[V](base), opts...), format: func(v []V) string {
+		return formatSlice(v, separator, func(u V) string { return formatUint(u, base) }, opts...)
+	}}
+}
+
+// DurationSliceFlag returns a [flag.Value] storing into p values parsed like [GetDurationSlice].
+func DurationSliceFlag(p *[]time.Duration, separator string, opts ...SliceOption) flag.Value {
+	return &flagValue[[]time.Duration]{p: p, parse: sliceParser(separator, time.ParseDuration, opts...), format: func(v []time.Duration) string {
+		return formatSlice(v, separator, time.Duration.String, opts...)
+	}}
+}
+
+// URLSliceFlag returns a [flag.Value] storing into p values parsed like [GetURLSlice].
+func URLSliceFlag(p *[]url.URL, separator string, opts ...SliceOption) flag.Value {
+	return &flagValue[[]url.URL]{p: p, parse: sliceParser(separator, parseURL, opts...), format: func(v []url.URL) string {
+		return formatSlice(v, separator, formatURL, opts...)
+	}}
+}
@@ -0,0 +1,170 @@
+package env
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagKey(t *testing.T) {
+	if key := FlagKey("APP_", "http-addr"); key != "APP_HTTP_ADDR" {
+		t.Errorf("expected key %s got %s", "APP_HTTP_ADDR", key)
+	}
+}
+
+func TestSetFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	addr := fs.String("http-addr", ":8080", "")
+	timeout := fs.Duration("timeout", time.Second, "")
+	level := fs.String("level", "info", "")
+	ports := []int{80}
+	fs.Var(IntSliceFlag(&ports, ",", 10), "ports", "")
+
+	t.Setenv("KEY_FLAG_HTTP_ADDR", ":9090")
+	t.Setenv("KEY_FLAG_TIMEOUT", "5s")
+	t.Setenv("KEY_FLAG_PORTS", "443,8443")
+
+	if err := fs.Parse([]string{"-timeout", "10s"}); err != nil {
+		t.Fatalf("parse flags failed %s", err.Error())
+	}
+
+	if err := SetFlags(PassedFlags(fs), "KEY_FLAG_"); err != nil {
+		t.Errorf("set flags failed %s", err.Error())
+	}
+
+	if *addr != ":9090" {
+		t.Errorf("expected env var value %s got %s", ":9090", *addr)
+	}
+
+	if *timeout != 10*time.Second {
+		t.Errorf("expected explicit flag value %s got %s", 10*time.Second, *timeout)
+	}
+
+	if *level != "info" {
+		t.Errorf("expected flag default %s got %s", "info", *level)
+	}
+
+	if err := equalSlices(ports, []int{443, 8443}); err != nil {
+		t.Errorf("expected env var value %s", err.Error())
+	}
+}
+
+func TestSetFlagsParseError(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var ports []uint16
+	fs.Var(UintSliceFlag(&ports, ",", 10), "ports", "")
+
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("parse flags failed %s", err.Error())
+	}
+
+	src := NewMap(map[string]string{"KEY_FLAG_PORTS": "80,8o8o"})
+
+	var parseErr *ParseError
+	if err := SetFlagsFrom(src, PassedFlags(fs), "KEY_FLAG_"); !errors.As(err, &parseErr) || parseErr.Key != "KEY_FLAG_PORTS" {
+		t.Errorf("expected parse error got %v", err)
+	}
+}
+
+func TestFlagSource(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("level", "info", "")
+	fs.String("http-addr", ":8080", "")
+
+	if err := fs.Parse([]string{"-level", "debug"}); err != nil {
+		t.Fatalf("parse flags failed %s", err.Error())
+	}
+
+	src := Layers(
+		Layer{Name: "os", Source: NewMap(map[string]string{"APP_LEVEL": "warn", "APP_HTTP_ADDR": ":9090"})},
+		Layer{Name: "flags", Source: FlagSource(PassedFlags(fs), "APP_")},
+	)
+
+	if val := GetStringFrom(src, "APP_LEVEL", ""); val != "debug" {
+		t.Errorf("expected flag value %s got %s", "debug", val)
+	}
+
+	if val := GetStringFrom(src, "APP_HTTP_ADDR", ""); val != ":9090" {
+		t.Errorf("expected env var value %s got %s", ":9090", val)
+	}
+}
+
+func TestFlagSourceAfterSetFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("level", "info", "")
+	fs.String("http-addr", ":8080", "")
+
+	if err := fs.Parse([]string{"-level", "debug"}); err != nil {
+		t.Fatalf("parse flags failed %s", err.Error())
+	}
+
+	passed := PassedFlags(fs)
+
+	env := NewMap(map[string]string{"APP_LEVEL": "warn", "APP_HTTP_ADDR": ":9000"})
+	if err := SetFlagsFrom(env, passed, "APP_"); err != nil {
+		t.Fatalf("set flags failed %s", err.Error())
+	}
+
+	flags := FlagSource(passed, "APP_")
+
+	if val, ok := flags.Lookup("APP_HTTP_ADDR"); ok {
+		t.Errorf("expected flag set from the environment to be missing got %s", val)
+	}
+
+	if val, _ := flags.Lookup("APP_LEVEL"); val != "debug" {
+		t.Errorf("expected flag value %s got %s", "debug", val)
+	}
+
+	if err := SetFlagsFrom(NewMap(map[string]string{"APP_HTTP_ADDR": ":9090"}), passed, "APP_"); err != nil {
+		t.Fatalf("set flags failed %s", err.Error())
+	}
+
+	if val := fs.Lookup("http-addr").Value.String(); val != ":9090" {
+		t.Errorf("expected env var value %s got %s", ":9090", val)
+	}
+}
+
+func TestFlagValueString(t *testing.T) {
+	durations := []time.Duration{time.Second, time.Minute}
+
+	if s := DurationSliceFlag(&durations, ";").String(); s != "1s;1m0s" {
+		t.Errorf("expected %s got %s", "1s;1m0s", s)
+	}
+
+	var n int
+	v := IntFlag(&n, 16)
+	if err := v.Set("ff"); err != nil || n != 255 {
+		t.Errorf("expected value %d got %d %v", 255, n, err)
+	}
+}
+
+func TestFlagInvalidBase(t *testing.T) {
+	var (
+		i  int
+		u  uint
+		is []int
+		us []uint
+	)
+
+	for name, value := range map[string]func(){
+		"IntFlag":       func() { IntFlag(&i, 1) },
+		"UintFlag":      func() { UintFlag(&u, 40) },
+		"IntSliceFlag":  func() { IntSliceFlag(&is, ",", -1) },
+		"UintSliceFlag": func() { UintSliceFlag(&us, ",", 37) },
+	} {
+		func() {
+			defer func() {
+				if r, _ := recover().(string); !strings.HasPrefix(r, "env: invalid base ") || !strings.HasSuffix(r, " for "+name) {
+					t.Errorf("expected %s to panic with an invalid base got %q", name, r)
+				}
+			}()
+
+			value()
+		}()
+	}
+}
@@ -0,0 +1,246 @@
+package env
+
+import (
+	"net/url"
+	"time"
+)
+
+// GetStringFrom is like [GetString] but resolves the variable through src instead of the
+// package [Source].
+func GetStringFrom[V String](src Source, key string, defaultValue V) V {
+	return getFrom(src, key, defaultValue, parseString[V])
+}
+
+// GetBoolFrom is like [GetBool] but resolves the variable through src instead of the
+// package [Source].
+func GetBoolFrom[V Boolean](src Source, key string, defaultValue V) V {
+	return getFrom(src, key, defaultValue, parseBool[V])
+}
+
+// GetIntFrom is like [GetInt] but resolves the variable through src instead of the
+// package [Source].
+func GetIntFrom[V Signed](src Source, key string, base int, defaultValue V) V {
+	return getFrom(src, key, defaultValue, intParser[V](base))
+}
+
+// GetUintFrom is like [GetUint] but resolves the variable through src instead of the
+// package [Source].
+func GetUintFrom[V Unsigned](src Source, key string, base int, defaultValue V) V {
+	return getFrom(src, key, defaultValue, uintParser[V](base))
+}
+
+// GetFloatFrom is like [GetFloat] but resolves the variable through src instead of the
+// package [Source].
+func GetFloatFrom[V Float](src Source, key string, defaultValue V) V {
+	return getFrom(src, key, defaultValue, parseFloat[V])
+}
+
+// GetDurationFrom is like [GetDuration] but resolves the variable through src instead of the
+// package [Source].
+func GetDurationFrom(src Source, key string, defaultValue time.Duration) time.Duration {
+	return getFrom(src, key, defaultValue, time.ParseDuration)
+}
+
+// GetURLFrom is like [GetURL] but resolves the variable through src instead of the
+// package [Source].
+func GetURLFrom(src Source, key string, defaultValue url.URL) url.URL {
+	return getFrom(src, key, defaultValue, parseURL)
+}
+
+// GetStringSliceFrom is like [GetStringSlice] but resolves the variable through src instead
+// of the package [Source].
+func GetStringSliceFrom[V String](src Source, key, separator string, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(src, key, separator, defaultValue, parseString[V], opts)
+}
+
+// GetBoolSliceFrom is like [GetBoolSlice] but resolves the variable through src instead
+// of the package [Source].
+func GetBoolSliceFrom[V Boolean](src Source, key, separator string, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(src, key, separator, defaultValue, parseBool[V], opts)
+}
+
+// GetIntSliceFrom is like [GetIntSlice] but resolves the variable through src instead
+// of the package [Source].
+func GetIntSliceFrom[V Signed](src Source, key, separator string, base int, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(src, key, separator, defaultValue, intParser[V](base), opts)
+}
+
+// GetUintSliceFrom is like [GetUintSlice] but resolves the variable through src instead
+// of the package [Source].
+func GetUintSliceFrom[V Unsigned](src Source, key, separator string, base int, defaultValue []V, opts ...SliceOption) []V {
+	return getSlice(src, key, separator, defaultValue, uintParser[V](base), opts)
+}
+
+// GetDurationSliceFrom is like [GetDurationSlice] but resolves the variable through src
+// instead of the package [Source].
+func GetDurationSliceFrom(src Source, key, separator string, defaultValue []time.Duration, opts ...SliceOption) []time.Duration {
+	return getSlice(src, key, separator, defaultValue, time.ParseDuration, opts)
+}
+
+// GetURLSliceFrom is like [GetURLSlice] but resolves the variable through src instead
+// of the package [Source].
+func GetURLSliceFrom(src Source, key, separator string, defaultValue []url.URL, opts ...SliceOption) []url.URL {
+	return getSlice(src, key, separator, defaultValue, parseURL, opts)
+}
+
+// LookupStringSliceFrom is like [LookupStringSlice] but resolves the variable through src
+// instead of the package [Source].
+func LookupStringSliceFrom[V String](src Source, key, separator string, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(src, key, separator, parseString[V], opts)
+}
+
+// LookupBoolSliceFrom is like [LookupBoolSlice] but resolves the variable through src instead
+// of the package [Source].
+func LookupBoolSliceFrom[V Boolean](src Source, key, separator string, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(src, key, separator, parseBool[V], opts)
+}
+
+// LookupIntSliceFrom is like [LookupIntSlice] but resolves the variable through src instead of
+// the package [Source].
+func LookupIntSliceFrom[V Signed](src Source, key, separator string, base int, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(src, key, separator, intParser[V](base), opts)
+}
+
+// LookupUintSliceFrom is like [LookupUintSlice] but resolves the variable through src instead
+// of the package [Source].
+func LookupUintSliceFrom[V Unsigned](src Source, key, separator string, base int, opts ...SliceOption) ([]V, error) {
+	return lookupSlice(src, key, separator, uintParser[V](base), opts)
+}
+
+// LookupDurationSliceFrom is like [LookupDurationSlice] but resolves the variable through src
+// instead of the package [Source].
+func LookupDurationSliceFrom(src Source, key, separator string, opts ...SliceOption) ([]time.Duration, error) {
+	return lookupSlice(src, key, separator, time.ParseDuration, opts)
+}
+
+// LookupURLSliceFrom is like [LookupURLSlice] but resolves the variable through src instead of
+// the package [Source].
+func LookupURLSliceFrom(src Source, key, separator string, opts ...SliceOption) ([]url.URL, error) {
+	return lookupSlice(src, key, separator, parseURL, opts)
+}
+
+// GetStringMapFrom is like [GetStringMap] but resolves the variables through src instead of
+// the package [Source].
+func GetStringMapFrom[V String](src Source, prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(src, prefix, separator, defaultValue, parseString[V])
+}
+
+// GetBoolMapFrom is like [GetBoolMap] but resolves the variables through src instead of the
+// package [Source].
+func GetBoolMapFrom[V Boolean](src Source, prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(src, prefix, separator, defaultValue, parseBool[V])
+}
+
+// GetIntMapFrom is like [GetIntMap] but resolves the variables through src instead of the
+// package [Source].
+func GetIntMapFrom[V Signed](src Source, prefix, separator string, base int, defaultValue map[string]V) map[string]V {
+	return getMap(src, prefix, separator, defaultValue, intParser[V](base))
+}
+
+// GetUintMapFrom is like [GetUintMap] but resolves the variables through src instead of the
+// package [Source].
+func GetUintMapFrom[V Unsigned](src Source, prefix, separator string, base int, defaultValue map[string]V) map[string]V {
+	return getMap(src, prefix, separator, defaultValue, uintParser[V](base))
+}
+
+// GetFloatMapFrom is like [GetFloatMap] but resolves the variables through src instead of the
+// package [Source].
+func GetFloatMapFrom[V Float](src Source, prefix, separator string, defaultValue map[string]V) map[string]V {
+	return getMap(src, prefix, separator, defaultValue, parseFloat[V])
+}
+
+// GetDurationMapFrom is like [GetDurationMap] but resolves the variables through src instead
+// of the package [Source].
+func GetDurationMapFrom(src Source, prefix, separator string, defaultValue map[string]time.Duration) map[string]time.Duration {
+	return getMap(src, prefix, separator, defaultValue, time.ParseDuration)
+}
+
+// GetURLMapFrom is like [GetURLMap] but resolves the variables through src instead of the
+// package [Source].
+func GetURLMapFrom(src Source, prefix, separator string, defaultValue map[string]url.URL) map[string]url.URL {
+	return getMap(src, prefix, separator, defaultValue, parseURL)
+}
+
+// MaybeStringFrom is like [MaybeString] but resolves the variable through src instead of the
+// package [Source].
+func MaybeStringFrom[V String](src Source, key string) Optional[V] {
+	return maybe(src, key, parseString[V])
+}
+
+// MaybeBoolFrom is like [MaybeBool] but resolves the variable through src instead of the
+// package [Source].
+func MaybeBoolFrom[V Boolean](src Source, key string) Optional[V] {
+	return maybe(src, key, parseBool[V])
+}
+
+// MaybeIntFrom is like [MaybeInt] but resolves the variable through src instead of the package
+// [Source].
+func MaybeIntFrom[V Signed](src Source, key string, base int) Optional[V] {
+	return maybe(src, key, intParser[V](base))
+}
+
+// MaybeUintFrom is like [MaybeUint] but resolves the variable through src instead of the
+// package [Source].
+func MaybeUintFrom[V Unsigned](src Source, key string, base int) Optional[V] {
+	return maybe(src, key, uintParser[V](base))
+}
+
+// MaybeFloatFrom is like [MaybeFloat] but resolves the variable through src instead of the
+// package [Source].
+func MaybeFloatFrom[V Float](src Source, key string) Optional[V] {
+	return maybe(src, key, parseFloat[V])
+}
+
+// MaybeDurationFrom is like [MaybeDuration] but resolves the variable through src instead of
+// the package [Source].
+func MaybeDurationFrom(src Source, key string) Optional[time.Duration] {
+	return maybe(src, key, time.ParseDuration)
+}
+
+// MaybeURLFrom is like [MaybeURL] but resolves the variable through src instead of the package
+// [Source].
+func MaybeURLFrom(src Source, key string) Optional[url.URL] {
+	return maybe(src, key, parseURL)
+}
+
+// MaybeStringSliceFrom is like [MaybeStringSlice] but resolves the variable through src
+// instead of the package [Source].
+func MaybeStringSliceFrom[V String](src Source, key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(src, key, separator, parseString[V], opts)
+}
+
+// MaybeBoolSliceFrom is like [MaybeBoolSlice] but resolves the variable through src instead of
+// the package [Source].
+func MaybeBoolSliceFrom[V Boolean](src Source, key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(src, key, separator, parseBool[V], opts)
+}
+
+// MaybeIntSliceFrom is like [MaybeIntSlice] but resolves the variable through src instead of
+// the package [Source].
+func MaybeIntSliceFrom[V Signed](src Source, key, separator string, base int, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(src, key, separator, intParser[V](base), opts)
+}
+
+// MaybeUintSliceFrom is like [MaybeUintSlice] but resolves the variable through src instead of
+// the package [Source].
+func MaybeUintSliceFrom[V Unsigned](src Source, key, separator string, base int, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(src, key, separator, uintParser[V](base), opts)
+}
+
+// MaybeFloatSliceFrom is like [MaybeFloatSlice] but resolves the variable through src instead
+// of the package [Source].
+func MaybeFloatSliceFrom[V Float](src Source, key, separator string, opts ...SliceOption) Optional[[]V] {
+	return maybeSlice(src, key, separator, parseFloat[V], opts)
+}
+
+// MaybeDurationSliceFrom is like [MaybeDurationSlice] but resolves the variable through src
+// instead of the package [Source].
+func MaybeDurationSliceFrom(src Source, key, separator string, opts ...SliceOption) Optional[[]time.Duration] {
+	return maybeSlice(src, key, separator, time.ParseDuration, opts)
+}
+
+// MaybeURLSliceFrom is like [MaybeURLSlice] but resolves the variable through src instead of
+// the package [Source].
+func MaybeURLSliceFrom(src Source, key, separator string, opts ...SliceOption) Optional[[]url.URL] {
+	return maybeSlice(src, key, separator, parseURL, opts)
+}
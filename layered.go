@@ -0,0 +1,157 @@
+package env
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Layer is a named [Source] stacked in a [Layered] source.
+type Layer struct {
+	// Name identifies the layer in provenances and explanations, e.g. "defaults" or "flags".
+	Name string
+	// Source provides the values of the layer.
+	Source Source
+}
+
+// Layered is a [Source] resolving every key through a stack of layers. Layers are given in
+// increasing order of precedence, so a value present in a later layer overrides the values
+// of all earlier layers.
+type Layered struct {
+	layers []Layer
+}
+
+// Layers returns a [Layered] source stacking the layers in increasing order of precedence,
+// for example defaults, a checked-in dotenv file, a local override file, the process
+// environment and command-line flags.
+func Layers(layers ...Layer) *Layered {
+	return &Layered{layers: append([]Layer(nil), layers...)}
+}
+
+// Lookup retrieves the value of the variable named by the key from the layer with the
+// highest precedence it is present in.
+func (l *Layered) Lookup(key string) (string, bool) {
+	if layer, ok := l.winner(key); ok {
+		return layer.Source.Lookup(key)
+	}
+
+	return "", false
+}
+
+func (l *Layered) winner(key string) (Layer, bool) {
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		if _, ok := l.layers[i].Source.Lookup(key); ok {
+			return l.layers[i], true
+		}
+	}
+
+	return Layer{}, false
+}
+
+// Keys returns the keys present in any layer in lexicographical order.
+func (l *Layered) Keys() []string {
+	seen := make(map[string]struct{})
+
+	var keys []string
+
+	for _, layer := range l.layers {
+		for _, key := range layer.Source.Keys() {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Provenance returns the provenance of the variable named by the key in the layer it is
+// resolved from, with the name of the layer as the source.
+func (l *Layered) Provenance(key string) Provenance {
+	layer, _ := l.winner(key)
+
+	p := provenanceOf(layer.Source, key)
+	p.Source = layer.Name
+
+	return p
+}
+
+// LayerValue is the value of a key in a single layer of a [Layered] source.
+type LayerValue struct {
+	// Layer is the name of the layer.
+	Layer string
+	// Value is the value of the key in the layer.
+	Value string
+	// Present reports whether the key is present in the layer.
+	Present bool
+}
+
+// Explanation describes how a key is resolved by a [Layered] source.
+type Explanation struct {
+	// Key is the name of the environment variable.
+	Key string
+	// Values holds the value of the key in every layer in increasing order of precedence.
+	Values []LayerValue
+	// Winner is the name of the layer the key is resolved from, or empty if the key is not
+	// present in any layer.
+	Winner string
+}
+
+// Explain returns the value of the variable named by the key in every layer and which one
+// of them wins.
+func (l *Layered) Explain(key string) Explanation {
+	e := Explanation{Key: key}
+
+	for _, layer := range l.layers {
+		val, ok := layer.Source.Lookup(key)
+		e.Values = append(e.Values, LayerValue{Layer: layer.Name, Value: val, Present: ok})
+	}
+
+	if layer, ok := l.winner(key); ok {
+		e.Winner = layer.Name
+	}
+
+	return e
+}
+
+// String formats the explanation with one line per layer. Values of variables marked as
+// secret with [Var.MarkSecret] are redacted.
+func (e Explanation) String() string {
+	var b strings.Builder
+
+	b.WriteString(e.Key)
+
+	winner := -1
+	for i, v := range e.Values {
+		if v.Present {
+			winner = i
+		}
+	}
+
+	for i, v := range e.Values {
+		fmt.Fprintf(&b, "\n  %s: ", v.Layer)
+
+		switch {
+		case !v.Present:
+			b.WriteString("not set")
+		case isSecret(e.Key):
+			b.WriteString(redacted)
+		default:
+			b.WriteString(strconv.Quote(v.Value))
+		}
+
+		if i == winner {
+			b.WriteString(" (wins)")
+		}
+	}
+
+	if winner < 0 {
+		b.WriteString("\n  not set in any layer")
+	}
+
+	return b.String()
+}
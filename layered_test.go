@@ -0,0 +1,73 @@
+package env
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayered(t *testing.T) {
+	dotenv, err := ParseDotenv(strings.NewReader("KEY_LAYER_TIMEOUT=10s\nKEY_LAYER_LEVEL=info"), "app.env")
+	if err != nil {
+		t.Fatalf("parse dotenv failed %s", err.Error())
+	}
+
+	t.Setenv("KEY_LAYER_TIMEOUT", "20s")
+
+	src := Layers(
+		Layer{Name: "defaults", Source: NewMap(map[string]string{"KEY_LAYER_TIMEOUT": "5s", "KEY_LAYER_PORT": "80"})},
+		Layer{Name: "dotenv", Source: dotenv},
+		Layer{Name: "os", Source: OS()},
+	)
+
+	if val := GetDurationFrom(src, "KEY_LAYER_TIMEOUT", 0); val != 20*time.Second {
+		t.Errorf("expected os value %s got %s", 20*time.Second, val)
+	}
+
+	if val := GetStringFrom(src, "KEY_LAYER_LEVEL", ""); val != "info" {
+		t.Errorf("expected dotenv value %q got %q", "info", val)
+	}
+
+	if val := GetIntFrom(src, "KEY_LAYER_PORT", 10, 0); val != 80 {
+		t.Errorf("expected defaults value %d got %d", 80, val)
+	}
+
+	if p := src.Provenance("KEY_LAYER_LEVEL"); p.Source != "dotenv" || p.File != "app.env" || p.Line != 2 {
+		t.Errorf("unexpected provenance %s", p)
+	}
+
+	keys := src.Keys()
+	for _, key := range []string{"KEY_LAYER_LEVEL", "KEY_LAYER_PORT", "KEY_LAYER_TIMEOUT"} {
+		found := false
+		for _, k := range keys {
+			found = found || k == key
+		}
+
+		if !found {
+			t.Errorf("expected key %s in %v", key, keys)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	src := Layers(
+		Layer{Name: "defaults", Source: NewMap(map[string]string{"KEY_EXPLAIN": "5s"})},
+		Layer{Name: "dotenv", Source: NewMap(nil)},
+		Layer{Name: "os", Source: NewMap(map[string]string{"KEY_EXPLAIN": "10s"})},
+	)
+
+	e := src.Explain("KEY_EXPLAIN")
+	if e.Winner != "os" {
+		t.Errorf("expected winner %s got %s", "os", e.Winner)
+	}
+
+	expected := "KEY_EXPLAIN\n  defaults: \"5s\"\n  dotenv: not set\n  os: \"10s\" (wins)"
+	if e.String() != expected {
+		t.Errorf("expected explanation %q got %q", expected, e.String())
+	}
+
+	expected = "KEY_EXPLAIN_MISSING\n  defaults: not set\n  dotenv: not set\n  os: not set\n  not set in any layer"
+	if s := src.Explain("KEY_EXPLAIN_MISSING").String(); s != expected {
+		t.Errorf("expected explanation %q got %q", expected, s)
+	}
+}
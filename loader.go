@@ -0,0 +1,151 @@
+package env
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Loader is a [Source] collecting the errors of the getters resolving variables through it,
+// so a configuration can be read with the *From getters and validated as a whole. It is the
+// source used by the loaders generated by cmd/envgen.
+//
+//	l := env.NewLoader(nil, map[string]string{"TIMEOUT": "5s"})
+//	l.Require("DATABASE_URL")
+//	cfg.DatabaseURL = env.GetURLFrom(l, "DATABASE_URL", cfg.DatabaseURL)
+//	cfg.Timeout = env.GetDurationFrom(l, "TIMEOUT", cfg.Timeout)
+//	if err := l.Err(); err != nil {
+//		...
+//	}
+type Loader struct {
+	src      Source
+	defaults map[string]string
+
+	mu       sync.Mutex
+	required map[string]struct{}
+	errs     []error
+}
+
+// lookupObserver is implemented by sources observing the reads of the getters.
+type lookupObserver interface {
+	observeMissing(key string)
+	observeParseError(err *ParseError)
+}
+
+// NewLoader returns a [Loader] resolving variables through src, or through the package
+// [Source] if src is nil. The defaults hold the values of variables not present in src,
+// written the way they would be in the environment.
+func NewLoader(src Source, defaults map[string]string) *Loader {
+	if src == nil {
+		src = currentSource()
+	}
+
+	l := &Loader{
+		src:      src,
+		defaults: make(map[string]string, len(defaults)),
+		required: make(map[string]struct{}),
+	}
+
+	for key, val := range defaults {
+		l.defaults[key] = val
+	}
+
+	return l
+}
+
+// Require marks the variable named by the key as required. A [*RequiredError] is collected
+// when a getter reads a required variable that is neither present nor has a default.
+func (l *Loader) Require(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.required[key] = struct{}{}
+}
+
+// Secret marks the variable named by the key as holding a secret value, so that it is
+// redacted whenever it is logged or rendered, like a struct field tagged as secret.
+func (l *Loader) Secret(key string) {
+	secretKeys.Store(key, struct{}{})
+}
+
+// Err returns the errors collected so far joined in the order they occurred: a
+// [*RequiredError] for every missing required variable and a [*ParseError] for every value
+// that could not be parsed.
+func (l *Loader) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return errors.Join(l.errs...)
+}
+
+// Lookup retrieves the value of the variable named by the key, or its default value if it is
+// not present, or empty under the [EmptyAsUnset] policy.
+func (l *Loader) Lookup(key string) (string, bool) {
+	if val, ok := l.lookupSource(key); ok {
+		return val, true
+	}
+
+	val, ok := l.defaults[key]
+
+	return val, ok
+}
+
+// Keys returns the keys of all variables present or with a default value in lexicographical
+// order.
+func (l *Loader) Keys() []string {
+	keys := l.src.Keys()
+
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		seen[key] = struct{}{}
+	}
+
+	for key := range l.defaults {
+		if _, ok := seen[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Provenance returns the provenance of the variable named by the key.
+func (l *Loader) Provenance(key string) Provenance {
+	if _, ok := l.lookupSource(key); !ok {
+		if _, ok := l.defaults[key]; ok {
+			return Provenance{Key: key, Source: ProvenanceDefault}
+		}
+	}
+
+	return provenanceOf(l.src, key)
+}
+
+// lookupSource retrieves the value of the variable named by the key from the source of the
+// loader, handling an empty value as missing under the [EmptyAsUnset] policy so that the
+// default value applies.
+func (l *Loader) lookupSource(key string) (string, bool) {
+	val, ok := l.src.Lookup(key)
+	if ok && val == "" && currentEmptyPolicy() == EmptyAsUnset {
+		return "", false
+	}
+
+	return val, ok
+}
+
+func (l *Loader) observeMissing(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.required[key]; ok {
+		l.errs = append(l.errs, &RequiredError{Key: key})
+	}
+}
+
+func (l *Loader) observeParseError(err *ParseError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.errs = append(l.errs, err)
+}
@@ -0,0 +1,62 @@
+package env
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoader(t *testing.T) {
+	src := NewMap(map[string]string{
+		"KEY_LOADER_PORT":  "80a",
+		"KEY_LOADER_DEBUG": "true",
+	})
+
+	l := NewLoader(src, map[string]string{"KEY_LOADER_TIMEOUT": "5s", "KEY_LOADER_DEBUG": "false"})
+	l.Require("KEY_LOADER_URL")
+	l.Require("KEY_LOADER_TIMEOUT")
+
+	if val := GetIntFrom(l, "KEY_LOADER_PORT", 10, 8080); val != 8080 {
+		t.Errorf("expected default value 8080 got %d", val)
+	}
+
+	if val := GetBoolFrom(l, "KEY_LOADER_DEBUG", false); !val {
+		t.Errorf("expected value from source got %t", val)
+	}
+
+	if val := GetDurationFrom(l, "KEY_LOADER_TIMEOUT", 0); val != 5*time.Second {
+		t.Errorf("expected default 5s got %s", val)
+	}
+
+	GetStringFrom(l, "KEY_LOADER_URL", "")
+	GetStringFrom(l, "KEY_LOADER_OPTIONAL", "")
+
+	err := l.Err()
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "KEY_LOADER_PORT" {
+		t.Errorf("expected parse error got %v", err)
+	}
+
+	var requiredErr *RequiredError
+	if !errors.As(err, &requiredErr) || requiredErr.Key != "KEY_LOADER_URL" {
+		t.Errorf("expected required error got %v", err)
+	}
+
+	if p := l.Provenance("KEY_LOADER_TIMEOUT"); p.Source != ProvenanceDefault {
+		t.Errorf("expected provenance %s got %s", ProvenanceDefault, p.Source)
+	}
+
+	if p := l.Provenance("KEY_LOADER_DEBUG"); p.Source != ProvenanceMap {
+		t.Errorf("expected provenance %s got %s", ProvenanceMap, p.Source)
+	}
+
+	keys := l.Keys()
+	if err := equalSlices(keys, []string{"KEY_LOADER_DEBUG", "KEY_LOADER_PORT", "KEY_LOADER_TIMEOUT"}); err != nil {
+		t.Errorf("expected keys got %v: %s", keys, err.Error())
+	}
+
+	if err := NewLoader(src, nil).Err(); err != nil {
+		t.Errorf("expected no error got %s", err.Error())
+	}
+}
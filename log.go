@@ -0,0 +1,94 @@
+package env
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+var logger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the logger the getters report to. Every key read is logged at debug level
+// with its resolved value, source and whether the default value was used, and every value
+// that could not be parsed is logged at warn level. Values of variables marked as secret
+// with [Var.MarkSecret], or bound to struct fields tagged as secret, are redacted. Warnings,
+// see [SetWarningHandler], are logged to the logger as well. Passing nil disables logging,
+// which is the default.
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// secretKeys holds the keys of struct fields tagged as secret, see [Unmarshal].
+var secretKeys sync.Map
+
+func isSecret(key string) bool {
+	if _, ok := secretKeys.Load(key); ok {
+		return true
+	}
+
+	v := LookupVariable(key)
+
+	return v != nil && v.Secret
+}
+
+// logRead logs a read of the variable named by the key at debug level.
+func logRead(key string, value any, p Provenance, usedDefault bool) {
+	l := logger.Load()
+	if l == nil || !l.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	l.LogAttrs(context.Background(), slog.LevelDebug, "env: read variable",
+		slog.String("key", key),
+		slog.Any("value", logValue(key, value)),
+		slog.String("source", p.Source),
+		slog.Bool("default", usedDefault),
+	)
+}
+
+// logParseError logs at warn level that the value of the variable named by the key could
+// not be parsed and the default value was used instead.
+func logParseError(key, value string, err error) {
+	logInvalid("env: invalid value, using default", key, value, err)
+}
+
+// logSkippedElements logs at warn level that invalid elements of the value of the variable
+// named by the key were skipped, see [SliceSkipInvalid].
+func logSkippedElements(key, value string, err error) {
+	logInvalid("env: invalid elements, skipping them", key, value, err)
+}
+
+func logInvalid(msg, key, value string, err error) {
+	l := logger.Load()
+	if l == nil {
+		return
+	}
+
+	errAttr := slog.Any("error", err)
+	if isSecret(key) {
+		errAttr = slog.String("error", redacted)
+	}
+
+	l.LogAttrs(context.Background(), slog.LevelWarn, msg,
+		slog.String("key", key),
+		slog.Any("value", logValue(key, value)),
+		errAttr,
+	)
+}
+
+func logValue(key string, value any) any {
+	if isSecret(key) {
+		return redacted
+	}
+
+	switch v := value.(type) {
+	case url.URL:
+		return v.String()
+	case []url.URL:
+		return formatSlice(v, " ", formatURL)
+	default:
+		return v
+	}
+}
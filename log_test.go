@@ -0,0 +1,97 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var b bytes.Buffer
+
+	SetLogger(slog.New(slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { SetLogger(nil) })
+
+	return &b
+}
+
+func logRecords(t *testing.T, b *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("unmarshal log record failed %s", err.Error())
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	b := captureLogs(t)
+
+	t.Setenv("KEY_LOG_PORT", "8080")
+	GetInt("KEY_LOG_PORT", 10, 0)
+	GetInt("KEY_LOG_MISSING", 10, 7)
+
+	t.Setenv("KEY_LOG_INVALID", "80a")
+	GetInt("KEY_LOG_INVALID", 10, 0)
+
+	records := logRecords(t, b)
+	if len(records) != 4 {
+		t.Fatalf("expected 4 log records got %d: %s", len(records), b.String())
+	}
+
+	expected := []struct {
+		level  string
+		key    string
+		value  any
+		source any
+	}{
+		{"DEBUG", "KEY_LOG_PORT", "8080", ProvenanceOS},
+		{"DEBUG", "KEY_LOG_MISSING", float64(7), ProvenanceDefault},
+		{"WARN", "KEY_LOG_INVALID", "80a", nil},
+		{"DEBUG", "KEY_LOG_INVALID", float64(0), ProvenanceDefaultAfterParseError},
+	}
+
+	for i, e := range expected {
+		r := records[i]
+		if r["level"] != e.level || r["key"] != e.key || r["value"] != e.value || r["source"] != e.source {
+			t.Errorf("unexpected log record %d %v", i, r)
+		}
+	}
+}
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	isolateRegistry(t)
+
+	b := captureLogs(t)
+
+	v := IntVar("KEY_LOG_SECRET", 10, 0, "").MarkSecret()
+
+	t.Setenv("KEY_LOG_SECRET", "1234x")
+	v.Get()
+
+	if strings.Contains(b.String(), "1234") {
+		t.Errorf("expected secret value to be redacted got %s", b.String())
+	}
+}
+
+func TestLoggerWarnings(t *testing.T) {
+	b := captureLogs(t)
+
+	warn(&DeprecatedKeyError{Key: "OLD", Replacement: "NEW"})
+
+	if !strings.Contains(b.String(), "env: OLD is deprecated, use NEW instead") {
+		t.Errorf("expected warning to be logged got %s", b.String())
+	}
+}
@@ -0,0 +1,74 @@
+package env
+
+import (
+	"sort"
+	"strings"
+)
+
+// getMap resolves the map named by the prefix through src, parsing the value of every entry
+// with parse. The entries are the variables named by the prefix followed by the separator and
+// their name, which does not contain the separator. The defaultValue is returned if there is
+// no entry or the value of any entry could not be parsed.
+func getMap[V any](src Source, prefix, separator string, defaultValue map[string]V, parse func(string) (V, error)) map[string]V {
+	names := mapNames(src.Keys(), prefix+separator, separator, false)
+
+	m := make(map[string]V, len(names))
+	invalid := false
+
+	for _, name := range names {
+		var zero V
+
+		val, ok, err := lookupValue(src, prefix+separator+name, zero, parse)
+
+		switch {
+		case err != nil:
+			invalid = true
+		case ok:
+			m[name] = val
+		}
+	}
+
+	switch {
+	case invalid:
+		return defaultValue
+	case len(m) == 0:
+		return missingValue(src, prefix, defaultValue)
+	default:
+		return m
+	}
+}
+
+// mapNames returns the sorted names of the entries of a map found in keys, which are the keys
+// with the prefix followed by the name. If nested is false the name is the rest of the key and
+// keys whose rest contains the separator are ignored, otherwise the entries are structs and
+// the name is followed by the separator and the key of a field.
+func mapNames(keys []string, prefix, separator string, nested bool) []string {
+	if separator == "" {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+
+	var names []string
+
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+
+		name, field, found := strings.Cut(rest, separator)
+		if name == "" || found != nested || (nested && field == "") {
+			continue
+		}
+
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
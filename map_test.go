@@ -0,0 +1,82 @@
+package env
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestGetMap(t *testing.T) {
+	src := NewMap(map[string]string{
+		"LIMIT__USERS":         "10",
+		"LIMIT__SESSIONS":      "20",
+		"LIMIT__USERS__BURST":  "5",
+		"LIMIT__":              "1",
+		"LIMITS__OTHER":        "1",
+		"TIMEOUT__READ":        "5s",
+		"TIMEOUT__WRITE":       "1x",
+		"CACHE__USERS__TTL":    "5m",
+		"CACHE__SESSIONS__TTL": "1h",
+	})
+
+	limits := GetIntMapFrom(src, "LIMIT", "__", 10, map[string]int{"DEFAULT": 1})
+	if !maps.Equal(limits, map[string]int{"USERS": 10, "SESSIONS": 20}) {
+		t.Errorf("expected the entries of LIMIT got %v", limits)
+	}
+
+	timeouts := GetDurationMapFrom(src, "TIMEOUT", "__", map[string]time.Duration{"DEFAULT": time.Second})
+	if !maps.Equal(timeouts, map[string]time.Duration{"DEFAULT": time.Second}) {
+		t.Errorf("expected default value with an invalid entry got %v", timeouts)
+	}
+
+	ttls := GetDurationMapFrom(src, "CACHE__USERS", "__", nil)
+	if !maps.Equal(ttls, map[string]time.Duration{"TTL": 5 * time.Minute}) {
+		t.Errorf("expected the entries of CACHE__USERS got %v", ttls)
+	}
+
+	if p := provenanceFor("LIMIT__USERS"); p.Source != "map" {
+		t.Errorf("expected provenance of the entry got %+v", p)
+	}
+
+	l := NewLoader(src, map[string]string{"PORT__HTTP": "8080"})
+	l.Require("MISSING")
+
+	ports := GetUintMapFrom[uint16](l, "PORT", "__", 10, nil)
+	if !maps.Equal(ports, map[string]uint16{"HTTP": 8080}) {
+		t.Errorf("expected the loader defaults got %v", ports)
+	}
+
+	GetStringMapFrom(l, "MISSING", "__", map[string]string{})
+	GetDurationMapFrom(l, "TIMEOUT", "__", nil)
+
+	var (
+		requiredErr *RequiredError
+		parseErr    *ParseError
+	)
+
+	err := l.Err()
+	if !errors.As(err, &requiredErr) || requiredErr.Key != "MISSING" {
+		t.Errorf("expected required error for MISSING got %v", err)
+	}
+
+	if !errors.As(err, &parseErr) || parseErr.Key != "TIMEOUT__WRITE" {
+		t.Errorf("expected parse error for TIMEOUT__WRITE got %v", err)
+	}
+}
+
+func TestMapNames(t *testing.T) {
+	keys := []string{"CACHE_A_TTL", "CACHE_A_SIZE", "CACHE_B_TTL", "CACHE_C", "CACHE__TTL", "CACHE_D_", "OTHER_A_TTL"}
+
+	if err := equalSlices(mapNames(keys, "CACHE_", "_", true), []string{"A", "B"}); err != nil {
+		t.Errorf("expected the entries of a map of structs %s", err.Error())
+	}
+
+	if err := equalSlices(mapNames(keys, "CACHE_", "_", false), []string{"C"}); err != nil {
+		t.Errorf("expected the entries of a map %s", err.Error())
+	}
+
+	if names := mapNames(keys, "CACHE_", "", false); len(names) != 0 {
+		t.Errorf("expected no entries without a separator got %v", names)
+	}
+}
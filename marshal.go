@@ -0,0 +1,434 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Marshal returns the environment form, KEY=value, of cfg, which must be a struct, a pointer
+// to a struct or a map[string]string. The result can be passed as the Env of an
+// [os/exec.Cmd] or written with [WriteDotenv].
+//
+// Struct fields are bound with the env tag, see [Unmarshal]. Values are formatted with the
+// separator and base of their field so that they parse back to the same value with the
+// matching getter: durations are formatted with [time.Duration.String] and URLs with
+// [net/url.URL.String]. Indexed slices and slices of structs are returned as one variable
+// per element, and map fields as one variable per entry in lexicographical order of their
+// keys. [Optional] fields are only returned if they are set, and empty slices are left out.
+// Variables are returned in field order, or sorted by key for maps.
+//
+// An error is returned if a slice has an element containing its separator and is neither
+// quoted nor escaped, see [SliceQuoted] and [SliceEscaped], since it would not be read back
+// as the same elements.
+func Marshal(cfg any) ([]string, error) {
+	if m, ok := cfg.(map[string]string); ok {
+		vars := make([]string, 0, len(m))
+		for key, val := range m {
+			vars = append(vars, key+"="+val)
+		}
+
+		sort.Strings(vars)
+
+		return vars, nil
+	}
+
+	v, err := structValue(cfg, false)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := structFields(v.Type(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalFields(v, fields)
+}
+
+// marshalFields returns the environment form of the fields of the struct v.
+func marshalFields(v reflect.Value, fields []structField) ([]string, error) {
+	vars := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.index)
+
+		if f.optional {
+			val, ok := optionalOf(fv).get()
+			if !ok {
+				continue
+			}
+
+			fv = reflect.ValueOf(val)
+		}
+
+		switch {
+		case isStructMap(f.typ):
+			for _, name := range mapKeys(fv) {
+				prefix := f.entryPrefix(name.String())
+
+				entryVars, err := marshalFields(fv.MapIndex(name), prefixFields(f.elems, prefix))
+				if err != nil {
+					return nil, err
+				}
+
+				vars = append(vars, entryVars...)
+			}
+		case f.typ.Kind() == reflect.Map:
+			entry := f.entry()
+
+			for _, name := range mapKeys(fv) {
+				if isEmptySlice(fv.MapIndex(name)) {
+					continue
+				}
+
+				val, err := entry.formatValue(fv.MapIndex(name))
+				if err != nil {
+					return nil, err
+				}
+
+				vars = append(vars, f.entryKey(name.String())+"="+val)
+			}
+		case isStructSlice(f.typ):
+			for i := 0; i < fv.Len(); i++ {
+				prefix := f.elemPrefix(f.slice.indexed.start + i)
+
+				elemVars, err := marshalFields(fv.Index(i), prefixFields(f.elems, prefix))
+				if err != nil {
+					return nil, err
+				}
+
+				vars = append(vars, elemVars...)
+			}
+		case f.slice.indexed != nil:
+			for i := 0; i < fv.Len(); i++ {
+				key := f.slice.indexed.key(f.key, f.slice.indexed.start+i)
+				vars = append(vars, key+"="+formatScalar(fv.Index(i), f.base))
+			}
+		case isEmptySlice(fv):
+			// An empty value is not a valid slice, so empty slices are left out like
+			// unset optional fields.
+		default:
+			val, err := f.formatValue(fv)
+			if err != nil {
+				return nil, err
+			}
+
+			vars = append(vars, f.key+"="+val)
+		}
+	}
+
+	return vars, nil
+}
+
+func isEmptySlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Len() == 0
+}
+
+// mapKeys returns the keys of the map v in lexicographical order.
+func mapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	return keys
+}
+
+// WriteDotenv writes the variables, in the KEY=value form returned by [Marshal] and
+// [os.Environ], to w as a dotenv file that [ParseDotenv] reads back to the same values.
+// Values that can not be written verbatim are double quoted.
+func WriteDotenv(w io.Writer, vars []string) error {
+	var b strings.Builder
+
+	for _, kv := range vars {
+		key, val, ok := strings.Cut(kv, "=")
+		if !ok || !validDotenvKey(key) {
+			return fmt.Errorf("env: invalid variable %q", kv)
+		}
+
+		fmt.Fprintf(&b, "%s=%s\n", key, dotenvQuote(val))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// RequiredError is returned by [Unmarshal] for a field tagged as required whose variable is
+// not present, and collected by a [Loader] for a required variable that is not present.
+type RequiredError struct {
+	// Key is the name of the missing environment variable.
+	Key string
+}
+
+func (e *RequiredError) Error() string {
+	return fmt.Sprintf("env: required variable %s is not set", e.Key)
+}
+
+// Unmarshal sets the fields of the struct cfg points to from the package [Source].
+//
+// A field is bound to the variable named by its env tag, which holds the key followed by the
+// comma separated options required and secret, e.g. `env:"DB_PASSWORD,required,secret"`. A
+// field tagged `env:"-"` is ignored. The envSeparator tag sets the separator of slice fields,
+// "," by default, and the envSlice tag the comma separated names of their [SliceOption]:
+// trimspace, dropempty, quoted, escaped and skipinvalid. The envBase tag sets the base of
+// integer fields, 10 by default, and the envDefault tag sets the value used when the variable
+// is not present. Struct fields without an env tag are bound recursively, with their keys
+// prefixed by their envPrefix tag.
+//
+// A slice field tagged with envIndexed is read from one variable per element, named by its
+// key followed by the value of the tag and the index of the element, see [SliceIndexed]: a
+// field tagged `env:"HOSTS" envIndexed:"_"` is read from HOSTS_0, HOSTS_1 and so on. Slices of
+// structs without an env tag are read the same way, with the keys of the fields of every
+// element prefixed by the envPrefix tag of the slice followed by the index of the element and
+// an underscore: the URL field of the elements of a field tagged `envPrefix:"UPSTREAM_"` is
+// read from UPSTREAM_0_URL, UPSTREAM_1_URL and so on, up to the first index without any
+// variable. Indexes start at 0, or at the value of the envIndexStart tag. The envPrefix tag is
+// required on slices of structs.
+//
+// A map field keyed by strings is read from one variable per entry, named by its key followed
+// by the separator set by the envMapSeparator tag, "_" by default, and the key of the entry,
+// see [GetStringMap]: a field tagged `env:"LIMIT"` is read from LIMIT_USERS, LIMIT_SESSIONS and
+// so on, and keeps its value if there is no entry. Maps of structs without an env tag are read
+// the same way, with the keys of the fields of every entry prefixed by the envPrefix tag of
+// the map followed by the key of the entry and the separator: the TTL field of the entries of
+// a field tagged `envPrefix:"CACHE__" envMapSeparator:"__"` is read from CACHE__USERS__TTL,
+// CACHE__SESSIONS__TTL and so on. Map fields can not have a default.
+//
+// A field of type [Optional] is bound like a field of the type of its value, and is set only
+// if its variable is present: to the parsed value, or to a value that is not set holding the
+// [*ParseError] if the value could not be parsed. Optional fields can not have a default, so
+// that the fields that are set are exactly the variables that are present.
+//
+// The envMin and envMax tags set the inclusive range of numeric and duration fields and the
+// envEnum tag sets the comma separated values a field accepts; they apply to every element of
+// slice fields and to every entry of map fields. The envUsage tag describes the variable, see
+// [SchemaOf].
+//
+// Fields are parsed with the same rules as the getter for their type, and values outside the
+// range or the allowed values of their field are rejected like values that can not be parsed.
+// A field whose variable is not present and has no default keeps its value. The returned
+// error joins a [*RequiredError] for every missing required variable and a [*ParseError] for
+// every value that could not be parsed, whose fields keep their values unless invalid
+// elements of a slice are skipped.
+func Unmarshal(cfg any) error {
+	return UnmarshalFrom(currentSource(), cfg)
+}
+
+// UnmarshalFrom is like [Unmarshal] but reads the variables from src instead of the package
+// [Source].
+func UnmarshalFrom(src Source, cfg any) error {
+	v, err := structValue(cfg, true)
+	if err != nil {
+		return err
+	}
+
+	fields, err := structFields(v.Type(), "")
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(unmarshalFields(src, v, fields)...)
+}
+
+// unmarshalFields sets the fields of the struct v from src and returns the errors of the
+// fields that could not be set.
+func unmarshalFields(src Source, v reflect.Value, fields []structField) []error {
+	var errs []error
+
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.index)
+
+		switch {
+		case isStructSlice(f.typ):
+			errs = append(errs, unmarshalElements(src, fv, f)...)
+			continue
+		case isStructMap(f.typ):
+			errs = append(errs, unmarshalStructEntries(src, fv, f)...)
+			continue
+		case f.typ.Kind() == reflect.Map:
+			errs = append(errs, unmarshalEntries(src, fv, f)...)
+			continue
+		case f.optional:
+			errs = append(errs, unmarshalOptional(src, fv, f)...)
+			continue
+		}
+
+		if f.secret {
+			secretKeys.Store(f.key, struct{}{})
+		}
+
+		parsed, ok, err := f.lookup(src, fv.Interface())
+		if err != nil {
+			// The field keeps its value, unless invalid elements of a slice were skipped.
+			errs = append(errs, err)
+		}
+
+		switch {
+		case ok:
+			fv.Set(reflect.ValueOf(parsed))
+		case f.hasDefault:
+			parsedDefault, err := f.parseValue(f.defaultValue)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("env: invalid default for %s: %w", f.key, err))
+				continue
+			}
+
+			fv.Set(parsedDefault)
+		case f.required:
+			errs = append(errs, &RequiredError{Key: f.key})
+		}
+	}
+
+	return errs
+}
+
+// unmarshalElements sets the slice of structs fv from the variables of its elements, up to
+// the first index without any variable of src prefixed by [structField.elemPrefix]. The slice
+// keeps its value if there is no element.
+func unmarshalElements(src Source, fv reflect.Value, f structField) []error {
+	keys := src.Keys()
+
+	var errs []error
+
+	slice := reflect.MakeSlice(f.typ, 0, 0)
+
+	for i := f.slice.indexed.start; hasKeyWithPrefix(keys, f.elemPrefix(i)); i++ {
+		elem := reflect.New(f.typ.Elem()).Elem()
+		errs = append(errs, unmarshalFields(src, elem, prefixFields(f.elems, f.elemPrefix(i)))...)
+		slice = reflect.Append(slice, elem)
+	}
+
+	if slice.Len() > 0 {
+		fv.Set(slice)
+	}
+
+	return errs
+}
+
+// lookup resolves the variable of the field through src with [lookupValue], parsing its
+// value with [structField.parseValue].
+func (f structField) lookup(src Source, defaultValue any) (any, bool, error) {
+	src, parse := bindSlice(f.slice, src, f.separator, f.parseValue, f.parseElements)
+
+	return lookupValue(src, f.key, defaultValue, func(s string) (any, error) {
+		parsed, err := parse(s)
+		if !parsed.IsValid() {
+			return nil, err
+		}
+
+		return parsed.Interface(), err
+	})
+}
+
+// lookupPresent is like [structField.lookup] but returns a nil value if the variable is not
+// present or its value could not be parsed, rather than a default value, so that such a value
+// is told apart from one whose invalid elements were skipped.
+func (f structField) lookupPresent(src Source) (any, bool, error) {
+	parsed, ok, err := f.lookup(src, nil)
+	if ok && parsed == nil && err == nil {
+		// The value is empty under the EmptyAsZero policy.
+		parsed = zeroLike(reflect.Zero(f.typ).Interface())
+	}
+
+	return parsed, ok, err
+}
+
+// unmarshalOptional sets the Optional fv from the variable of its field, or keeps its value if
+// the variable is not present. An invalid value sets fv to a value that is not set holding
+// the [*ParseError].
+func unmarshalOptional(src Source, fv reflect.Value, f structField) []error {
+	if f.secret {
+		secretKeys.Store(f.key, struct{}{})
+	}
+
+	parsed, ok, err := f.lookupPresent(src)
+
+	switch {
+	case ok:
+		optionalOf(fv).setValue(parsed, err)
+	case f.required:
+		return []error{&RequiredError{Key: f.key}}
+	}
+
+	if err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
+
+// unmarshalEntries sets the map fv from the variables of its entries, named by
+// [structField.entryKey]. The map keeps its value if there is no entry or the value of any
+// entry could not be parsed.
+func unmarshalEntries(src Source, fv reflect.Value, f structField) []error {
+	entry := f.entry()
+
+	var errs []error
+
+	m := reflect.MakeMap(f.typ)
+
+	for _, name := range mapNames(src.Keys(), f.key+f.mapSeparator, f.mapSeparator, false) {
+		entry.key = f.entryKey(name)
+		if entry.secret {
+			secretKeys.Store(entry.key, struct{}{})
+		}
+
+		parsed, _, err := entry.lookupPresent(src)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if parsed == nil {
+			continue
+		}
+
+		m.SetMapIndex(reflect.ValueOf(name).Convert(f.typ.Key()), reflect.ValueOf(parsed))
+	}
+
+	switch {
+	case len(errs) > 0:
+	case m.Len() > 0:
+		fv.Set(m)
+	case f.required:
+		errs = append(errs, &RequiredError{Key: f.key})
+	}
+
+	return errs
+}
+
+// unmarshalStructEntries sets the map of structs fv from the variables of its entries, whose
+// keys are prefixed by [structField.entryPrefix]. The map keeps its value if there is no
+// entry.
+func unmarshalStructEntries(src Source, fv reflect.Value, f structField) []error {
+	names := mapNames(src.Keys(), f.key, f.mapSeparator, true)
+	if len(names) == 0 {
+		return nil
+	}
+
+	var errs []error
+
+	m := reflect.MakeMapWithSize(f.typ, len(names))
+
+	for _, name := range names {
+		elem := reflect.New(f.typ.Elem()).Elem()
+		errs = append(errs, unmarshalFields(src, elem, prefixFields(f.elems, f.entryPrefix(name)))...)
+		m.SetMapIndex(reflect.ValueOf(name).Convert(f.typ.Key()), elem)
+	}
+
+	fv.Set(m)
+
+	return errs
+}
+
+func hasKeyWithPrefix(keys []string, prefix string) bool {
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
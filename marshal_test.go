@@ -0,0 +1,475 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type marshalDatabase struct {
+	URL      url.URL `env:"URL"`
+	Password string  `env:"PASSWORD,secret"`
+}
+
+type marshalConfig struct {
+	Name     string          `env:"NAME"`
+	Debug    bool            `env:"DEBUG"`
+	Mask     uint32          `env:"MASK" envBase:"16"`
+	Offset   int8            `env:"OFFSET"`
+	Ratio    float32         `env:"RATIO"`
+	Timeout  time.Duration   `env:"TIMEOUT"`
+	Hosts    []string        `env:"HOSTS" envSeparator:" "`
+	Ports    []uint16        `env:"PORTS"`
+	Backoffs []time.Duration `env:"BACKOFFS" envSeparator:";"`
+	Database marshalDatabase `envPrefix:"DB_"`
+	Ignored  string          `env:"-"`
+	internal string
+}
+
+func testConfig(t *testing.T) marshalConfig {
+	u, err := url.ParseRequestURI("postgres://user@localhost:5432/app?sslmode=disable")
+	if err != nil {
+		t.Fatalf("parse url failed %s", err.Error())
+	}
+
+	return marshalConfig{
+		Name:     "say \"hi\" # not a comment",
+		Debug:    true,
+		Mask:     0xff00,
+		Offset:   -3,
+		Ratio:    0.1,
+		Timeout:  1500 * time.Millisecond,
+		Hosts:    []string{"a.example.com", "b.example.com"},
+		Ports:    []uint16{80, 443},
+		Backoffs: []time.Duration{time.Second, time.Minute},
+		Database: marshalDatabase{URL: *u, Password: "multi\nline"},
+		Ignored:  "ignored",
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	vars, err := Marshal(testConfig(t))
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	expected := []string{
+		"NAME=say \"hi\" # not a comment",
+		"DEBUG=true",
+		"MASK=ff00",
+		"OFFSET=-3",
+		"RATIO=0.1",
+		"TIMEOUT=1.5s",
+		"HOSTS=a.example.com b.example.com",
+		"PORTS=80,443",
+		"BACKOFFS=1s;1m0s",
+		"DB_URL=postgres://user@localhost:5432/app?sslmode=disable",
+		"DB_PASSWORD=multi\nline",
+	}
+
+	if err := equalSlices(vars, expected); err != nil {
+		t.Errorf("expected %q got %q: %s", expected, vars, err.Error())
+	}
+
+	vars, err = Marshal(map[string]string{"B": "2", "A": "1"})
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	if err := equalSlices(vars, []string{"A=1", "B=2"}); err != nil {
+		t.Errorf("expected sorted variables %s", err.Error())
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	cfg := testConfig(t)
+
+	vars, err := Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	var b bytes.Buffer
+	if err := WriteDotenv(&b, vars); err != nil {
+		t.Fatalf("write dotenv failed %s", err.Error())
+	}
+
+	d, err := ParseDotenv(&b, "round-trip.env")
+	if err != nil {
+		t.Fatalf("parse dotenv failed %s", err.Error())
+	}
+
+	var got marshalConfig
+	if err := UnmarshalFrom(d, &got); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	cfg.Ignored = ""
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("expected %+v got %+v", cfg, got)
+	}
+
+	if val := GetDurationFrom(d, "TIMEOUT", 0); val != cfg.Timeout {
+		t.Errorf("expected getter value %s got %s", cfg.Timeout, val)
+	}
+
+	if val := GetURLFrom(d, "DB_URL", url.URL{}); val.String() != cfg.Database.URL.String() {
+		t.Errorf("expected getter value %s got %s", cfg.Database.URL.String(), val.String())
+	}
+}
+
+func TestMarshalSliceSeparator(t *testing.T) {
+	type config struct {
+		Plain  []string `env:"S"`
+		Quoted []string `env:"Q" envSlice:"quoted"`
+	}
+
+	_, err := Marshal(config{Plain: []string{"a,b", "c"}})
+	if err == nil || !strings.Contains(err.Error(), `element "a,b" of S contains the separator ","`) {
+		t.Errorf("expected separator error got %v", err)
+	}
+
+	cfg := config{Quoted: []string{"a,b", "c"}}
+
+	vars, err := Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	var got config
+	if err := UnmarshalFrom(sourceOf(vars), &got); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("expected %+v got %+v", cfg, got)
+	}
+}
+
+func TestMarshalEmptySlice(t *testing.T) {
+	type config struct {
+		Ports []int  `env:"S"`
+		Name  string `env:"NAME"`
+	}
+
+	cfg := config{Ports: []int{}, Name: "api"}
+
+	vars, err := Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	if err := equalSlices(vars, []string{"NAME=api"}); err != nil {
+		t.Errorf("expected empty slice to be left out %s", err.Error())
+	}
+
+	var got config
+	if err := UnmarshalFrom(sourceOf(vars), &got); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if len(got.Ports) != 0 || got.Name != "api" {
+		t.Errorf("expected %+v got %+v", cfg, got)
+	}
+}
+
+// sourceOf returns a [Map] holding the variables in the KEY=value form.
+func sourceOf(vars []string) *Map {
+	values := make(map[string]string, len(vars))
+	for _, kv := range vars {
+		key, val, _ := strings.Cut(kv, "=")
+		values[key] = val
+	}
+
+	return NewMap(values)
+}
+
+func TestUnmarshal(t *testing.T) {
+	type config struct {
+		Port    int           `env:"KEY_UNMARSHAL_PORT,required"`
+		Timeout time.Duration `env:"KEY_UNMARSHAL_TIMEOUT" envDefault:"5s"`
+		Level   string        `env:"KEY_UNMARSHAL_LEVEL"`
+		Retries uint8         `env:"KEY_UNMARSHAL_RETRIES"`
+	}
+
+	t.Setenv("KEY_UNMARSHAL_RETRIES", "300")
+
+	cfg := config{Level: "info", Retries: 3}
+
+	err := Unmarshal(&cfg)
+
+	var requiredErr *RequiredError
+	if !errors.As(err, &requiredErr) || requiredErr.Key != "KEY_UNMARSHAL_PORT" {
+		t.Errorf("expected required error got %v", err)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "KEY_UNMARSHAL_RETRIES" {
+		t.Errorf("expected parse error got %v", err)
+	}
+
+	expected := config{Timeout: 5 * time.Second, Level: "info", Retries: 3}
+	if cfg != expected {
+		t.Errorf("expected %+v got %+v", expected, cfg)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var cfg struct {
+		Values map[int]string `env:"VALUES"`
+	}
+
+	if err := Unmarshal(&cfg); err == nil || err.Error() != "env: field Values has unsupported type map[int]string" {
+		t.Errorf("expected unsupported type error got %v", err)
+	}
+
+	if err := Unmarshal(cfg); err == nil {
+		t.Errorf("expected error for non pointer")
+	}
+
+	var unprefixed struct {
+		Upstreams []upstream
+	}
+
+	if err := Unmarshal(&unprefixed); err == nil || err.Error() != "env: field Upstreams is a slice of structs without an envPrefix tag" {
+		t.Errorf("expected missing prefix error got %v", err)
+	}
+
+	var invalidBase struct {
+		Mask int `env:"MASK" envBase:"40"`
+	}
+
+	if _, err := Marshal(invalidBase); err == nil || err.Error() != `env: field Mask has invalid base "40"` {
+		t.Errorf("expected invalid base error got %v", err)
+	}
+
+	if err := WriteDotenv(&bytes.Buffer{}, []string{"INVALID"}); err == nil {
+		t.Errorf("expected error for invalid variable")
+	}
+}
+
+type upstream struct {
+	URL    url.URL `env:"URL,required"`
+	Weight int     `env:"WEIGHT" envDefault:"1"`
+}
+
+type indexedConfig struct {
+	Hosts     []string   `env:"HOSTS" envIndexed:"_"`
+	Ports     []int      `env:"PORTS" envIndexed:"__" envIndexStart:"1"`
+	Upstreams []upstream `envPrefix:"UPSTREAM_"`
+}
+
+func TestUnmarshalIndexed(t *testing.T) {
+	vars := map[string]string{
+		"HOSTS_0":           "a",
+		"HOSTS_1":           "b,c",
+		"PORTS__1":          "80",
+		"PORTS__2":          "443",
+		"UPSTREAM_0_URL":    "http://a:8080",
+		"UPSTREAM_0_WEIGHT": "3",
+		"UPSTREAM_1_URL":    "http://b:8080",
+		"UPSTREAM_3_URL":    "http://after-gap:8080",
+	}
+
+	var cfg indexedConfig
+	if err := UnmarshalFrom(NewMap(vars), &cfg); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if err := equalSlices(cfg.Hosts, []string{"a", "b,c"}); err != nil {
+		t.Errorf("expected hosts %s", err.Error())
+	}
+
+	if err := equalSlices(cfg.Ports, []int{80, 443}); err != nil {
+		t.Errorf("expected ports %s", err.Error())
+	}
+
+	if len(cfg.Upstreams) != 2 || cfg.Upstreams[0].URL.Host != "a:8080" || cfg.Upstreams[0].Weight != 3 ||
+		cfg.Upstreams[1].URL.Host != "b:8080" || cfg.Upstreams[1].Weight != 1 {
+		t.Errorf("expected upstreams got %+v", cfg.Upstreams)
+	}
+
+	marshaled, err := Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	expected := []string{
+		"HOSTS_0=a",
+		"HOSTS_1=b,c",
+		"PORTS__1=80",
+		"PORTS__2=443",
+		"UPSTREAM_0_URL=http://a:8080",
+		"UPSTREAM_0_WEIGHT=3",
+		"UPSTREAM_1_URL=http://b:8080",
+		"UPSTREAM_1_WEIGHT=1",
+	}
+	if err := equalSlices(marshaled, expected); err != nil {
+		t.Errorf("expected indexed variables %s", err.Error())
+	}
+
+	err = UnmarshalFrom(NewMap(map[string]string{"UPSTREAM_0_WEIGHT": "x"}), &indexedConfig{})
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "UPSTREAM_0_WEIGHT" {
+		t.Errorf("expected parse error for UPSTREAM_0_WEIGHT got %v", err)
+	}
+
+	var requiredErr *RequiredError
+	if !errors.As(err, &requiredErr) || requiredErr.Key != "UPSTREAM_0_URL" {
+		t.Errorf("expected required error for UPSTREAM_0_URL got %v", err)
+	}
+}
+
+type cacheConfig struct {
+	TTL  time.Duration `env:"TTL,required"`
+	Size int           `env:"SIZE" envDefault:"100"`
+}
+
+type mapConfig struct {
+	Limits map[string]int         `env:"LIMIT" envMax:"50"`
+	Tags   map[string][]string    `env:"TAG" envMapSeparator:"."`
+	Caches map[string]cacheConfig `envPrefix:"CACHE__" envMapSeparator:"__"`
+}
+
+func TestUnmarshalMap(t *testing.T) {
+	vars := map[string]string{
+		"LIMIT_USERS":           "10",
+		"LIMIT_SESSIONS":        "20",
+		"TAG.web":               "a,b",
+		"CACHE__USERS__TTL":     "5m",
+		"CACHE__SESSIONS__TTL":  "1h",
+		"CACHE__SESSIONS__SIZE": "10",
+	}
+
+	var cfg mapConfig
+	if err := UnmarshalFrom(NewMap(vars), &cfg); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if len(cfg.Limits) != 2 || cfg.Limits["USERS"] != 10 || cfg.Limits["SESSIONS"] != 20 {
+		t.Errorf("expected limits got %v", cfg.Limits)
+	}
+
+	if err := equalSlices(cfg.Tags["web"], []string{"a", "b"}); err != nil || len(cfg.Tags) != 1 {
+		t.Errorf("expected tags got %v", cfg.Tags)
+	}
+
+	expectedCaches := map[string]cacheConfig{"USERS": {5 * time.Minute, 100}, "SESSIONS": {time.Hour, 10}}
+	if !reflect.DeepEqual(cfg.Caches, expectedCaches) {
+		t.Errorf("expected caches %v got %v", expectedCaches, cfg.Caches)
+	}
+
+	marshaled, err := Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	expected := []string{
+		"LIMIT_SESSIONS=20",
+		"LIMIT_USERS=10",
+		"TAG.web=a,b",
+		"CACHE__SESSIONS__TTL=1h0m0s",
+		"CACHE__SESSIONS__SIZE=10",
+		"CACHE__USERS__TTL=5m0s",
+		"CACHE__USERS__SIZE=100",
+	}
+	if err := equalSlices(marshaled, expected); err != nil {
+		t.Errorf("expected map variables %s", err.Error())
+	}
+
+	invalid := mapConfig{Limits: map[string]int{"USERS": 1}}
+
+	err = UnmarshalFrom(NewMap(map[string]string{"LIMIT_USERS": "10", "LIMIT_SESSIONS": "60", "CACHE__USERS__SIZE": "1"}), &invalid)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "LIMIT_SESSIONS" {
+		t.Errorf("expected parse error for LIMIT_SESSIONS got %v", err)
+	}
+
+	var requiredErr *RequiredError
+	if !errors.As(err, &requiredErr) || requiredErr.Key != "CACHE__USERS__TTL" {
+		t.Errorf("expected required error for CACHE__USERS__TTL got %v", err)
+	}
+
+	if len(invalid.Limits) != 1 || invalid.Limits["USERS"] != 1 {
+		t.Errorf("expected limits to keep their value got %v", invalid.Limits)
+	}
+
+	var withDefault struct {
+		Limits map[string]int `env:"LIMIT" envDefault:"1"`
+	}
+
+	err = UnmarshalFrom(NewMap(nil), &withDefault)
+	if err == nil || err.Error() != "env: field Limits is a map and can not have a default" {
+		t.Errorf("expected map default error got %v", err)
+	}
+}
+
+type overlayConfig struct {
+	Port    Optional[int]           `env:"PORT" envMax:"65535"`
+	Debug   Optional[bool]          `env:"DEBUG"`
+	Timeout Optional[time.Duration] `env:"TIMEOUT"`
+	Hosts   Optional[[]string]      `env:"HOSTS" envIndexed:"_"`
+}
+
+func TestUnmarshalOptional(t *testing.T) {
+	var overlay overlayConfig
+	if err := UnmarshalFrom(NewMap(map[string]string{"PORT": "0", "HOSTS_0": "a", "HOSTS_1": "b"}), &overlay); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if !overlay.Port.IsSet() || overlay.Port.Value() != 0 {
+		t.Errorf("expected port set to zero got %+v", overlay.Port)
+	}
+
+	if overlay.Debug.IsSet() || overlay.Timeout.IsSet() {
+		t.Errorf("expected debug and timeout to be unset got %+v", overlay)
+	}
+
+	if err := equalSlices(overlay.Hosts.Value(), []string{"a", "b"}); err != nil {
+		t.Errorf("expected hosts %s", err.Error())
+	}
+
+	marshaled, err := Marshal(overlay)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	if err := equalSlices(marshaled, []string{"PORT=0", "HOSTS_0=a", "HOSTS_1=b"}); err != nil {
+		t.Errorf("expected the set variables %s", err.Error())
+	}
+
+	err = UnmarshalFrom(NewMap(map[string]string{"PORT": "70000", "DEBUG": "true"}), &overlay)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "PORT" || !errors.Is(overlay.Port.Err(), parseErr) {
+		t.Errorf("expected parse error for PORT got %v", err)
+	}
+
+	if overlay.Port.IsSet() || !overlay.Debug.Value() || !overlay.Hosts.IsSet() {
+		t.Errorf("expected only present variables to be applied got %+v", overlay)
+	}
+
+	var withDefault struct {
+		Port Optional[int] `env:"PORT" envDefault:"80"`
+	}
+
+	err = UnmarshalFrom(NewMap(nil), &withDefault)
+	if err == nil || err.Error() != "env: field Port is optional and can not have a default" {
+		t.Errorf("expected optional default error got %v", err)
+	}
+
+	var optionalMap struct {
+		Limits Optional[map[string]int] `env:"LIMIT"`
+	}
+
+	err = UnmarshalFrom(NewMap(nil), &optionalMap)
+	if err == nil || err.Error() != "env: field Limits has unsupported type env.Optional[map[string]int]" {
+		t.Errorf("expected unsupported type error got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"reflect"
+)
+
+// Optional is the value of an environment variable that tells apart a variable that is not
+// present, one whose value could not be parsed and one that is set, as returned by the Maybe*
+// getters. The zero Optional is not set.
+//
+// A struct field of type Optional is bound like a field of the type of its value, see
+// [Unmarshal], and is only set if its variable is present, so that a struct of Optional
+// fields can be applied as an overlay of the variables actually present.
+type Optional[T any] struct {
+	value T
+	set   bool
+	err   error
+}
+
+// IsSet reports whether the variable is present with a value that could be parsed, or whose
+// invalid elements were skipped with [SliceSkipInvalid].
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Value returns the parsed value of the variable, or the zero value of T if it is not set.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Err returns the [*ParseError] of a value that could not be parsed, or nil if the variable
+// is not present or its value could be parsed.
+func (o Optional[T]) Err() error {
+	return o.err
+}
+
+// Or returns the parsed value of the variable if it is set, or defaultValue otherwise.
+func (o Optional[T]) Or(defaultValue T) T {
+	if !o.set {
+		return defaultValue
+	}
+
+	return o.value
+}
+
+// optionalField is implemented by the pointers to [Optional] values so that struct fields of
+// type Optional are bound like fields of the type of their value.
+type optionalField interface {
+	valueType() reflect.Type
+	get() (any, bool)
+	setValue(value any, err error)
+}
+
+var optionalFieldType = reflect.TypeOf((*optionalField)(nil)).Elem()
+
+func (o *Optional[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o *Optional[T]) get() (any, bool) {
+	return o.value, o.set
+}
+
+// setValue sets o to value, or to a value that is not set if value is nil, along with err.
+func (o *Optional[T]) setValue(value any, err error) {
+	*o = Optional[T]{err: err}
+	if value != nil {
+		o.value, o.set = value.(T), true
+	}
+}
+
+// isOptional reports whether t is an [Optional] type.
+func isOptional(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalFieldType)
+}
+
+// optionalOf returns the [optionalField] of the Optional v, copied if v is not addressable.
+func optionalOf(v reflect.Value) optionalField {
+	if !v.CanAddr() {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p.Elem()
+	}
+
+	return v.Addr().Interface().(optionalField)
+}
+
+// maybe resolves the environment variable named by the key through src into an [Optional].
+func maybe[V any](src Source, key string, parse func(string) (V, error)) Optional[V] {
+	// The default value is nil, so that a value that could not be parsed is told apart from
+	// one whose invalid elements were skipped.
+	val, ok, err := lookupValue(src, key, (*V)(nil), func(s string) (*V, error) {
+		parsed, err := parse(s)
+		return &parsed, err
+	})
+
+	switch {
+	case val != nil:
+		return Optional[V]{value: *val, set: true, err: err}
+	case ok && err == nil:
+		// The value is empty under the EmptyAsZero policy.
+		var zero V
+		return Optional[V]{value: zeroLike(zero), set: true}
+	default:
+		return Optional[V]{err: err}
+	}
+}
+
+// maybeSlice resolves the slice named by the key through src into an [Optional], splitting it
+// by the separator and parsing every element with parse as configured by opts.
+func maybeSlice[V any](src Source, key, separator string, parse func(string) (V, error), opts []SliceOption) Optional[[]V] {
+	src, parseSlice := sliceLookup(src, separator, parse, opts)
+
+	return maybe(src, key, parseSlice)
+}
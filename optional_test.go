@@ -0,0 +1,79 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMaybe(t *testing.T) {
+	src := NewMap(map[string]string{
+		"KEY_MAYBE_ZERO":    "0",
+		"KEY_MAYBE_INVALID": "x",
+		"KEY_MAYBE_PORTS":   "80,x,443",
+	})
+
+	unset := MaybeIntFrom[int](src, "KEY_MAYBE_UNSET", 10)
+	if unset.IsSet() || unset.Err() != nil || unset.Value() != 0 || unset.Or(42) != 42 {
+		t.Errorf("expected an unset value got %+v", unset)
+	}
+
+	zero := MaybeIntFrom[int](src, "KEY_MAYBE_ZERO", 10)
+	if !zero.IsSet() || zero.Err() != nil || zero.Value() != 0 || zero.Or(42) != 0 {
+		t.Errorf("expected a value set to zero got %+v", zero)
+	}
+
+	invalid := MaybeIntFrom[int](src, "KEY_MAYBE_INVALID", 10)
+
+	var parseErr *ParseError
+	if invalid.IsSet() || !errors.As(invalid.Err(), &parseErr) || parseErr.Key != "KEY_MAYBE_INVALID" || invalid.Or(42) != 42 {
+		t.Errorf("expected an invalid value got %+v", invalid)
+	}
+
+	ports := MaybeUintSliceFrom[uint16](src, "KEY_MAYBE_PORTS", ",", 10, SliceSkipInvalid())
+	if err := equalSlices(ports.Value(), []uint16{80, 443}); err != nil || !ports.IsSet() {
+		t.Errorf("expected valid elements %v", err)
+	}
+
+	if len(ElementErrors(ports.Err())) != 1 {
+		t.Errorf("expected the skipped element to be reported got %v", ports.Err())
+	}
+
+	ctx := WithValues(context.Background(), map[string]string{"KEY_MAYBE_CTX": "value"})
+	if val := MaybeStringCtx[string](ctx, "KEY_MAYBE_CTX"); val.Value() != "value" {
+		t.Errorf("expected context value got %+v", val)
+	}
+
+	l := NewLoader(src, nil)
+	l.Require("KEY_MAYBE_UNSET")
+
+	MaybeBoolFrom[bool](l, "KEY_MAYBE_UNSET")
+	MaybeBoolFrom[bool](l, "KEY_MAYBE_INVALID")
+
+	var requiredErr *RequiredError
+	if err := l.Err(); !errors.As(err, &requiredErr) || !errors.As(err, &parseErr) {
+		t.Errorf("expected required and parse errors got %v", err)
+	}
+}
+
+func TestMaybeEmpty(t *testing.T) {
+	src := NewMap(map[string]string{"KEY_MAYBE_EMPTY": ""})
+
+	setEmptyPolicy(t, EmptyAsUnset)
+
+	if val := MaybeStringFrom[string](src, "KEY_MAYBE_EMPTY"); val.IsSet() {
+		t.Errorf("expected an unset value got %+v", val)
+	}
+
+	setEmptyPolicy(t, EmptyAsZero)
+
+	if val := MaybeStringSliceFrom[string](src, "KEY_MAYBE_EMPTY", ","); !val.IsSet() || val.Value() == nil || len(val.Value()) != 0 {
+		t.Errorf("expected an empty slice got %+v", val)
+	}
+
+	setEmptyPolicy(t, EmptyAsError)
+
+	if val := MaybeIntFrom[int](src, "KEY_MAYBE_EMPTY", 10); val.IsSet() || !errors.Is(val.Err(), ErrEmpty) {
+		t.Errorf("expected ErrEmpty got %+v", val)
+	}
+}
@@ -0,0 +1,152 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Names of the sources recorded in a [Provenance].
+const (
+	// ProvenanceOS is recorded for values read from the process environment.
+	ProvenanceOS = "os"
+	// ProvenanceDotenv is recorded for values read from a dotenv file.
+	ProvenanceDotenv = "dotenv"
+	// ProvenanceFile is recorded for values read from a file named by a _FILE variable.
+	ProvenanceFile = "file"
+	// ProvenanceDefault is recorded when the variable was not present and the default value
+	// passed to the getter was returned.
+	ProvenanceDefault = "default"
+	// ProvenanceDefaultAfterParseError is recorded when the variable was present but could
+	// not be parsed and the default value passed to the getter was returned.
+	ProvenanceDefaultAfterParseError = "default-after-parse-error"
+)
+
+// Provenance records where the value returned for a key by the last getter reading it came
+// from.
+type Provenance struct {
+	// Key is the name of the environment variable.
+	Key string
+	// Source is the name of the source of the value, one of the Provenance constants for
+	// the sources provided by this package.
+	Source string
+	// File is the file the value was read from, if any.
+	File string
+	// Line is the line of File the value was defined on, or 0 if unknown.
+	Line int
+}
+
+func (p Provenance) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s from %s", p.Key, p.Source)
+
+	switch {
+	case p.File != "" && p.Line > 0:
+		fmt.Fprintf(&b, " (%s:%d)", p.File, p.Line)
+	case p.File != "":
+		fmt.Fprintf(&b, " (%s)", p.File)
+	}
+
+	return b.String()
+}
+
+// ProvenanceSource is implemented by sources that can describe where the value of a key
+// present in them was defined.
+type ProvenanceSource interface {
+	Source
+	// Provenance returns the provenance of the value of the key. It is only called for
+	// keys present in the source.
+	Provenance(key string) Provenance
+}
+
+func provenanceOf(src Source, key string) Provenance {
+	if ps, ok := src.(ProvenanceSource); ok {
+		return ps.Provenance(key)
+	}
+
+	return Provenance{Key: key, Source: fmt.Sprintf("%T", src)}
+}
+
+// tracking is set when the getters record the keys they read and the provenance of their
+// values, see [SetTracking].
+var tracking atomic.Bool
+
+// ErrTrackingDisabled is returned by [WriteProvenance] and [CheckUnknown], and the panic value
+// of [ProvenanceReport] and [UnknownKeys], when they are called without tracking enabled.
+var ErrTrackingDisabled = errors.New("env: tracking is disabled, see SetTracking")
+
+// SetTracking enables or disables recording the keys read by the getters and the provenance
+// of the values they return. The recorded reads are reported by [ProvenanceReport] and tell
+// [UnknownKeys] which variables are known. Tracking is disabled by default so that the
+// getters do not pay for the bookkeeping. Reads made while tracking is disabled are not
+// recorded, so call SetTracking before loading the configuration to report on it.
+func SetTracking(enabled bool) {
+	tracking.Store(enabled)
+}
+
+// observingReads reports whether the reads of the getters are recorded or logged, so that
+// describing them can be skipped otherwise.
+func observingReads() bool {
+	return tracking.Load() || logger.Load() != nil
+}
+
+var provenances sync.Map
+
+func recordProvenance(p Provenance) {
+	if !tracking.Load() {
+		return
+	}
+
+	if recorded, ok := provenances.Load(p.Key); ok && recorded.(Provenance) == p {
+		return
+	}
+
+	provenances.Store(p.Key, p)
+}
+
+// ProvenanceReport returns the provenance of the value returned by the last getter call for
+// every key read so far, sorted by key. Only the reads made while tracking is enabled are
+// reported, and ProvenanceReport panics with [ErrTrackingDisabled] if it is not enabled, see
+// [SetTracking].
+func ProvenanceReport() []Provenance {
+	if !tracking.Load() {
+		panic(ErrTrackingDisabled)
+	}
+
+	var report []Provenance
+
+	provenances.Range(func(_, p any) bool {
+		report = append(report, p.(Provenance))
+		return true
+	})
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Key < report[j].Key
+	})
+
+	return report
+}
+
+// WriteProvenance writes the [ProvenanceReport] to w, one key per line. It returns
+// [ErrTrackingDisabled] if tracking is not enabled, see [SetTracking].
+func WriteProvenance(w io.Writer) error {
+	if !tracking.Load() {
+		return ErrTrackingDisabled
+	}
+
+	var b strings.Builder
+
+	for _, p := range ProvenanceReport() {
+		b.WriteString(p.String())
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
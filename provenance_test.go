@@ -0,0 +1,111 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMain enables tracking, as an application reporting on its configuration would, so
+// that the tests can inspect the reads of the getters, see [SetTracking].
+func TestMain(m *testing.M) {
+	SetTracking(true)
+
+	os.Exit(m.Run())
+}
+
+func provenanceFor(key string) Provenance {
+	for _, p := range ProvenanceReport() {
+		if p.Key == key {
+			return p
+		}
+	}
+
+	return Provenance{}
+}
+
+func TestProvenance(t *testing.T) {
+	GetDuration("KEY_PROVENANCE_DEFAULT", time.Second)
+
+	if p := provenanceFor("KEY_PROVENANCE_DEFAULT"); p.Source != ProvenanceDefault {
+		t.Errorf("expected source %s got %s", ProvenanceDefault, p.Source)
+	}
+
+	t.Setenv("KEY_PROVENANCE_INVALID", "invalid")
+	GetDuration("KEY_PROVENANCE_INVALID", time.Second)
+
+	if p := provenanceFor("KEY_PROVENANCE_INVALID"); p.Source != ProvenanceDefaultAfterParseError {
+		t.Errorf("expected source %s got %s", ProvenanceDefaultAfterParseError, p.Source)
+	}
+
+	t.Setenv("KEY_PROVENANCE_OS", "1s")
+	GetDuration("KEY_PROVENANCE_OS", time.Second)
+
+	if p := provenanceFor("KEY_PROVENANCE_OS"); p.Source != ProvenanceOS {
+		t.Errorf("expected source %s got %s", ProvenanceOS, p.Source)
+	}
+}
+
+func TestWriteProvenance(t *testing.T) {
+	d, err := ParseDotenv(strings.NewReader("\nKEY_WRITE_PROVENANCE=1"), "test.env")
+	if err != nil {
+		t.Fatalf("parse dotenv failed %s", err.Error())
+	}
+
+	SetSource(d)
+	t.Cleanup(func() { SetSource(nil) })
+
+	GetBool("KEY_WRITE_PROVENANCE", false)
+
+	var b bytes.Buffer
+	if err := WriteProvenance(&b); err != nil {
+		t.Errorf("write provenance failed %s", err.Error())
+	}
+
+	expected := "KEY_WRITE_PROVENANCE from dotenv (test.env:2)\n"
+	if !strings.Contains(b.String(), expected) {
+		t.Errorf("expected report to contain %q got %q", expected, b.String())
+	}
+}
+
+func TestTrackingDisabled(t *testing.T) {
+	untracked(t)
+
+	t.Setenv("KEY_TRACKING_DISABLED", "1s")
+	GetDuration("KEY_TRACKING_DISABLED", time.Second)
+
+	if _, ok := provenances.Load("KEY_TRACKING_DISABLED"); ok {
+		t.Errorf("expected the provenance of KEY_TRACKING_DISABLED not to be recorded")
+	}
+
+	if _, ok := readKeys.Load("KEY_TRACKING_DISABLED"); ok {
+		t.Errorf("expected KEY_TRACKING_DISABLED not to be recorded as read")
+	}
+
+	if err := WriteProvenance(io.Discard); !errors.Is(err, ErrTrackingDisabled) {
+		t.Errorf("expected error %v got %v", ErrTrackingDisabled, err)
+	}
+
+	if err := CheckUnknown("KEY_TRACKING_"); !errors.Is(err, ErrTrackingDisabled) {
+		t.Errorf("expected error %v got %v", ErrTrackingDisabled, err)
+	}
+
+	for name, report := range map[string]func(){
+		"ProvenanceReport": func() { ProvenanceReport() },
+		"UnknownKeys":      func() { UnknownKeys("KEY_TRACKING_") },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrTrackingDisabled {
+					t.Errorf("expected %s to panic with %v got %v", name, ErrTrackingDisabled, r)
+				}
+			}()
+
+			report()
+		}()
+	}
+}
@@ -0,0 +1,185 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reloader loads a [Source] and reloads it on demand, on a polling interval or on a signal,
+// updating every [Value] watching it.
+type Reloader struct {
+	load func() (Source, error)
+
+	mu     sync.Mutex
+	src    atomic.Pointer[sourceHolder]
+	values []reloadable
+}
+
+type reloadable interface {
+	reload(src Source) error
+}
+
+// NewReloader returns a [Reloader] loading its [Source] with load, for example by reading a
+// dotenv file mounted from a ConfigMap. The source is loaded once before NewReloader returns.
+func NewReloader(load func() (Source, error)) (*Reloader, error) {
+	src, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reloader{load: load}
+	r.src.Store(&sourceHolder{src: src})
+
+	return r, nil
+}
+
+// Source returns the most recently loaded [Source].
+func (r *Reloader) Source() Source {
+	return r.src.Load().src
+}
+
+// Reload loads the [Source] again and updates every [Value] watching the reloader. If the
+// source could not be loaded the previous source is kept. A value that could not be parsed
+// keeps its previous value. The returned error joins every error encountered.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	src, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.src.Store(&sourceHolder{src: src})
+
+	var errs []error
+
+	for _, value := range r.values {
+		if err := value.reload(src); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Poll calls [Reloader.Reload] every interval until ctx is done. Errors returned by Reload
+// are passed to onError, which may be nil.
+func (r *Reloader) Poll(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// ReloadOnSignal calls [Reloader.Reload] every time the process receives one of the signals
+// until ctx is done. Errors returned by Reload are passed to onError, which may be nil. If no
+// signals are given, SIGHUP is used on Unix, and on other systems ReloadOnSignal never reloads
+// and only waits for ctx to be done.
+func (r *Reloader) ReloadOnSignal(ctx context.Context, onError func(error), signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = reloadSignals
+	}
+
+	if len(signals) == 0 {
+		// signal.Notify relays every signal when none are given.
+		<-ctx.Done()
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			if err := r.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Value holds the parsed value of a declared variable, atomically replaced every time the
+// [Reloader] it watches reloads.
+type Value[T any] struct {
+	v       *Var[T]
+	current atomic.Pointer[T]
+
+	mu        sync.Mutex
+	callbacks []func(oldValue, newValue T)
+}
+
+// Watch returns a [Value] resolving v through the [Source] of the reloader, now and on every
+// reload. If the variable could not be parsed the value starts with the default value of v
+// and the [*ParseError] is returned alongside it.
+func Watch[T any](r *Reloader, v *Var[T]) (*Value[T], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	val, err := v.Lookup(r.Source())
+
+	value := &Value[T]{v: v}
+	value.current.Store(&val)
+
+	r.values = append(r.values, value)
+
+	return value, err
+}
+
+// Load returns the current value.
+func (v *Value[T]) Load() T {
+	return *v.current.Load()
+}
+
+// OnChange registers fn to be called with the previous and the new value every time a
+// reload changes the value. Callbacks are called in the order they were registered from the
+// goroutine reloading.
+func (v *Value[T]) OnChange(fn func(oldValue, newValue T)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.callbacks = append(v.callbacks, fn)
+}
+
+func (v *Value[T]) reload(src Source) error {
+	newValue, err := v.v.Lookup(src)
+	if err != nil {
+		return err
+	}
+
+	oldValue := v.Load()
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+
+	v.current.Store(&newValue)
+
+	v.mu.Lock()
+	callbacks := append([]func(oldValue, newValue T){}, v.callbacks...)
+	v.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(oldValue, newValue)
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package env
+
+import "os"
+
+// reloadSignals are the signals [Reloader.ReloadOnSignal] reloads on when none are given.
+// There is no conventional reload signal outside of Unix, so the signals must be given.
+var reloadSignals []os.Signal
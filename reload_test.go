@@ -0,0 +1,101 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func dotenvReloader(t *testing.T, contents string) (*Reloader, string) {
+	path := filepath.Join(t.TempDir(), ".env")
+	writeDotenv(t, path, contents)
+
+	r, err := NewReloader(func() (Source, error) {
+		return LoadDotenv(path)
+	})
+	if err != nil {
+		t.Fatalf("new reloader failed %s", err.Error())
+	}
+
+	return r, path
+}
+
+func writeDotenv(t *testing.T, path, contents string) {
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write dotenv failed %s", err.Error())
+	}
+}
+
+func TestReload(t *testing.T) {
+	isolateRegistry(t)
+
+	r, path := dotenvReloader(t, "KEY_RELOAD_LEVEL=info")
+
+	level, err := Watch(r, StringVar("KEY_RELOAD_LEVEL", "warn", ""))
+	if err != nil {
+		t.Errorf("watch failed %s", err.Error())
+	}
+
+	if val := level.Load(); val != "info" {
+		t.Errorf("expected value %s got %s", "info", val)
+	}
+
+	var changes [][2]string
+	level.OnChange(func(oldValue, newValue string) {
+		changes = append(changes, [2]string{oldValue, newValue})
+	})
+
+	if err := r.Reload(); err != nil {
+		t.Errorf("reload failed %s", err.Error())
+	}
+
+	writeDotenv(t, path, "KEY_RELOAD_LEVEL=debug")
+
+	if err := r.Reload(); err != nil {
+		t.Errorf("reload failed %s", err.Error())
+	}
+
+	if val := level.Load(); val != "debug" {
+		t.Errorf("expected value %s got %s", "debug", val)
+	}
+
+	if len(changes) != 1 || changes[0] != [2]string{"info", "debug"} {
+		t.Errorf("expected a single change from info to debug got %v", changes)
+	}
+}
+
+func TestReloadParseError(t *testing.T) {
+	isolateRegistry(t)
+
+	r, path := dotenvReloader(t, "KEY_RELOAD_TIMEOUT=1s")
+
+	timeout, err := Watch(r, DurationVar("KEY_RELOAD_TIMEOUT", time.Minute, ""))
+	if err != nil {
+		t.Errorf("watch failed %s", err.Error())
+	}
+
+	writeDotenv(t, path, "KEY_RELOAD_TIMEOUT=invalid")
+
+	var parseErr *ParseError
+	if err := r.Reload(); !errors.As(err, &parseErr) || parseErr.Key != "KEY_RELOAD_TIMEOUT" {
+		t.Errorf("expected parse error got %v", err)
+	}
+
+	if val := timeout.Load(); val != time.Second {
+		t.Errorf("expected previous value %s got %s", time.Second, val)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove dotenv failed %s", err.Error())
+	}
+
+	if err := r.Reload(); err == nil {
+		t.Errorf("expected load error")
+	}
+
+	if val, _ := r.Source().Lookup("KEY_RELOAD_TIMEOUT"); val != "invalid" {
+		t.Errorf("expected previous source to be kept got %q", val)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package env
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadSignals are the signals [Reloader.ReloadOnSignal] reloads on when none are given.
+var reloadSignals = []os.Signal{syscall.SIGHUP}
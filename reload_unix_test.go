@@ -0,0 +1,64 @@
+//go:build unix
+
+package env
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	isolateRegistry(t)
+
+	r, path := dotenvReloader(t, "KEY_RELOAD_SIGNAL=1")
+
+	value, err := Watch(r, IntVar("KEY_RELOAD_SIGNAL", 10, 0, ""))
+	if err != nil {
+		t.Errorf("watch failed %s", err.Error())
+	}
+
+	changed := make(chan int, 1)
+	value.OnChange(func(_, newValue int) {
+		changed <- newValue
+	})
+
+	// Keep the default action of SIGUSR1 from terminating the test binary if it is
+	// delivered before ReloadOnSignal subscribed to it.
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGUSR1)
+	defer signal.Stop(guard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ready := make(chan struct{})
+	go func() {
+		close(ready)
+		r.ReloadOnSignal(ctx, nil, syscall.SIGUSR1)
+	}()
+	<-ready
+
+	writeDotenv(t, path, "KEY_RELOAD_SIGNAL=2")
+
+	deadline := time.After(5 * time.Second)
+	for {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatalf("kill failed %s", err.Error())
+		}
+
+		select {
+		case val := <-changed:
+			if val != 2 {
+				t.Errorf("expected value %d got %d", 2, val)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("expected reload on signal")
+		}
+	}
+}
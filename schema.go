@@ -0,0 +1,567 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema dialect of the schemas returned by [SchemaOf] and
+// [RegistrySchema].
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema document, or a subschema of one, describing environment
+// variables. Only the keywords needed to describe the types understood by the getters are
+// supported.
+//
+// The schema of a set of variables is an object with a property for every key. Booleans,
+// integers and floating point numbers are described by the boolean, integer and number
+// types, durations by strings of the go-duration format, which are bounded by the
+// formatMinimum and formatMaximum keywords, URLs by strings of the uri-reference format and
+// slices by arrays of their elements. Secret variables are marked as writeOnly. The
+// x-env-separator keyword holds the separator of the elements of an array in the environment,
+// the x-env-slice keyword the names of its [SliceOption] as in the envSlice tag of [Unmarshal]
+// and the x-env-base keyword the base of an integer if it is not 10.
+//
+// The variables of the elements of indexed slices, see [SliceIndexed], and of slices of
+// structs are described by pattern properties matching their keys for any index, e.g.
+// ^HOSTS_[0-9]+$ or ^UPSTREAM_[0-9]+_URL$. A required indexed slice requires its first
+// element. The variables of the entries of maps and of maps of structs are described the
+// same way for any key of an entry, e.g. ^LIMIT_[^_]+$ or ^CACHE_[^_]+_TTL$.
+type JSONSchema struct {
+	Schema            string                 `json:"$schema,omitempty"`
+	Type              string                 `json:"type,omitempty"`
+	Format            string                 `json:"format,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	Default           any                    `json:"default,omitempty"`
+	Enum              []any                  `json:"enum,omitempty"`
+	Minimum           json.Number            `json:"minimum,omitempty"`
+	Maximum           json.Number            `json:"maximum,omitempty"`
+	FormatMinimum     string                 `json:"formatMinimum,omitempty"`
+	FormatMaximum     string                 `json:"formatMaximum,omitempty"`
+	WriteOnly         bool                   `json:"writeOnly,omitempty"`
+	Items             *JSONSchema            `json:"items,omitempty"`
+	Properties        map[string]*JSONSchema `json:"properties,omitempty"`
+	PatternProperties map[string]*JSONSchema `json:"patternProperties,omitempty"`
+	Required          []string               `json:"required,omitempty"`
+	Separator         string                 `json:"x-env-separator,omitempty"`
+	Slice             []string               `json:"x-env-slice,omitempty"`
+	Base              *int                   `json:"x-env-base,omitempty"`
+}
+
+// SchemaOf returns the [JSONSchema] of the variables bound to the fields of cfg, a struct or
+// a pointer to a struct, following the tags described in [Unmarshal]. A variable is required
+// if its field is tagged as required and has no default.
+func SchemaOf(cfg any) (*JSONSchema, error) {
+	t := reflect.TypeOf(cfg)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("env: expected a struct or a pointer to a struct got %T", cfg)
+	}
+
+	fields, err := structFields(t, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return objectSchema(fields)
+}
+
+// RegistrySchema returns the [JSONSchema] of every declared variable. Variables marked with
+// [Var.MarkRequired] are required.
+func RegistrySchema() *JSONSchema {
+	var (
+		fields   []structField
+		required []string
+	)
+
+	VisitAll(func(v *Variable) {
+		registry.RLock()
+		b := registry.bindings[v.Key]
+		registry.RUnlock()
+
+		switch {
+		case v.Required && b.slice.indexed != nil:
+			required = append(required, b.slice.indexed.key(v.Key, b.slice.indexed.start))
+		case v.Required:
+			required = append(required, v.Key)
+		}
+
+		f := structField{
+			typ:          b.typ,
+			key:          v.Key,
+			separator:    b.separator,
+			slice:        b.slice,
+			base:         b.base,
+			defaultValue: v.Default,
+			required:     v.Required,
+			secret:       v.Secret,
+			usage:        v.Usage,
+		}
+
+		// Not every default formatted by the constructors parses back, like an empty slice
+		// or a zero url.URL, both formatted as an empty string. Such defaults are left out.
+		_, err := f.parseValue(v.Default)
+		f.hasDefault = err == nil
+
+		fields = append(fields, f)
+	})
+
+	// Invalid defaults are the only error of objectSchema and were left out above.
+	schema, err := objectSchema(fields)
+	if err != nil {
+		panic(err)
+	}
+
+	// Unlike struct fields, variables marked as required must be present even if they have a
+	// default value.
+	schema.Required = required
+
+	return schema
+}
+
+func objectSchema(fields []structField) (*JSONSchema, error) {
+	schema := &JSONSchema{
+		Schema:     JSONSchemaDialect,
+		Type:       "object",
+		Properties: make(map[string]*JSONSchema, len(fields)),
+	}
+
+	if err := schema.addFields(fields, ""); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(schema.Required)
+
+	return schema, nil
+}
+
+// addFields adds the variables of the fields to the schema. If pattern is not empty, it is
+// the regular expression matching the prefix of the keys of the elements of a slice of
+// structs, and the variables are added as pattern properties.
+func (s *JSONSchema) addFields(fields []structField, pattern string) error {
+	for _, f := range fields {
+		if isStructMap(f.typ) {
+			name := mapNamePattern(f.mapSeparator) + regexp.QuoteMeta(f.mapSeparator)
+			if err := s.addFields(f.elems, pattern+regexp.QuoteMeta(f.key)+name); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if isStructSlice(f.typ) {
+			if err := s.addFields(f.elems, pattern+regexp.QuoteMeta(f.key)+"[0-9]+_"); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		prop, err := f.schema()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case f.typ.Kind() == reflect.Map:
+			s.addPattern("^"+pattern+regexp.QuoteMeta(f.key+f.mapSeparator)+mapNamePattern(f.mapSeparator)+"$", prop)
+		case f.slice.indexed != nil:
+			s.addPattern("^"+pattern+regexp.QuoteMeta(f.key+f.slice.indexed.separator)+"[0-9]+$", prop)
+
+			if pattern == "" && f.required && !f.hasDefault {
+				s.Required = append(s.Required, f.slice.indexed.key(f.key, f.slice.indexed.start))
+			}
+		case pattern != "":
+			s.addPattern("^"+pattern+regexp.QuoteMeta(f.key)+"$", prop)
+		default:
+			s.Properties[f.key] = prop
+
+			if f.required && !f.hasDefault {
+				s.Required = append(s.Required, f.key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *JSONSchema) addPattern(pattern string, prop *JSONSchema) {
+	if s.PatternProperties == nil {
+		s.PatternProperties = make(map[string]*JSONSchema)
+	}
+
+	s.PatternProperties[pattern] = prop
+}
+
+// mapNamePattern returns the regular expression matching the keys of the entries of a map,
+// which do not contain the separator. A separator of more than one character can not be
+// excluded, so any key is matched.
+func mapNamePattern(separator string) string {
+	if len(separator) != 1 {
+		return ".+"
+	}
+
+	return "[^" + regexp.QuoteMeta(separator) + "]+"
+}
+
+// schema returns the schema of the variable of the field, or of the variables of its elements
+// if it is an indexed slice, or of its entries if it is a map.
+func (f structField) schema() (*JSONSchema, error) {
+	if f.typ.Kind() == reflect.Map {
+		return f.entry().schema()
+	}
+
+	s := f.scalarSchema()
+	if f.typ.Kind() == reflect.Slice && f.slice.indexed == nil {
+		s = &JSONSchema{Type: "array", Items: s, Separator: f.separator, Slice: f.slice.names()}
+	}
+
+	s.Description = f.usage
+	s.WriteOnly = f.secret
+
+	if f.hasDefault && f.slice.indexed == nil {
+		v, err := f.parseValue(f.defaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("env: invalid default for %s: %w", f.key, err)
+		}
+
+		s.Default = jsonValue(v)
+	}
+
+	return s, nil
+}
+
+func (f structField) scalarSchema() *JSONSchema {
+	t := f.elem()
+
+	s := &JSONSchema{}
+
+	switch {
+	case t == durationType:
+		s.Type, s.Format = "string", "go-duration"
+	case t == urlType:
+		s.Type, s.Format = "string", "uri-reference"
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			s.Type = "string"
+		case reflect.Bool:
+			s.Type = "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s.Type = "integer"
+			s.Minimum = json.Number(strconv.FormatInt(math.MinInt64>>(64-t.Bits()), 10))
+			s.Maximum = json.Number(strconv.FormatInt(math.MaxInt64>>(64-t.Bits()), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s.Type = "integer"
+			s.Minimum = "0"
+			s.Maximum = json.Number(strconv.FormatUint(math.MaxUint64>>(64-t.Bits()), 10))
+		case reflect.Float32, reflect.Float64:
+			s.Type = "number"
+		}
+	}
+
+	if s.Type == "integer" && f.base != 10 {
+		base := f.base
+		s.Base = &base
+	}
+
+	if f.min != nil {
+		if t == durationType {
+			s.FormatMinimum = formatScalar(*f.min, f.base)
+		} else {
+			s.Minimum = jsonValue(*f.min).(json.Number)
+		}
+	}
+
+	if f.max != nil {
+		if t == durationType {
+			s.FormatMaximum = formatScalar(*f.max, f.base)
+		} else {
+			s.Maximum = jsonValue(*f.max).(json.Number)
+		}
+	}
+
+	for _, e := range f.enum {
+		s.Enum = append(s.Enum, jsonValue(e))
+	}
+
+	return s
+}
+
+// jsonValue returns the JSON representation of a parsed value. Numbers are represented as
+// [json.Number] so that 64 bit integers are not rounded.
+func jsonValue(v reflect.Value) any {
+	if v.Type() == durationType || v.Type() == urlType {
+		return formatScalar(v, 10)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		values := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, jsonValue(v.Index(i)))
+		}
+
+		return values
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	default:
+		return json.Number(formatScalar(v, 10))
+	}
+}
+
+// Validate checks the values, keyed by the names of the variables, against the properties of
+// the schema. The returned error joins a [*RequiredError] for every missing required variable
+// and a [*ParseError] for every value that does not match the schema of its property or of a
+// pattern property matching its key, in lexicographical order of their keys. Values without
+// a property are ignored.
+func (s *JSONSchema) Validate(values map[string]string) error {
+	patterns := make(map[string]*regexp.Regexp, len(s.PatternProperties))
+	for pattern := range s.PatternProperties {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("env: invalid pattern property %q: %w", pattern, err)
+		}
+
+		patterns[pattern] = re
+	}
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+
+	for key := range values {
+		if _, ok := s.Properties[key]; !ok && matchAny(patterns, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	required := make(map[string]struct{}, len(s.Required))
+	for _, key := range s.Required {
+		required[key] = struct{}{}
+	}
+
+	var errs []error
+
+	for _, key := range keys {
+		val, ok := values[key]
+		if !ok {
+			if _, ok := required[key]; ok {
+				errs = append(errs, &RequiredError{Key: key})
+			}
+
+			continue
+		}
+
+		for _, prop := range s.propertiesOf(key, patterns) {
+			if err := prop.check(val); err != nil {
+				errs = append(errs, &ParseError{Key: key, Value: val, Err: err})
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// propertiesOf returns the schemas applying to the variable named by the key: its property
+// and the pattern properties matching the key, in lexicographical order of their patterns.
+func (s *JSONSchema) propertiesOf(key string, patterns map[string]*regexp.Regexp) []*JSONSchema {
+	var props []*JSONSchema
+	if prop, ok := s.Properties[key]; ok {
+		props = append(props, prop)
+	}
+
+	matching := make([]string, 0, len(patterns))
+	for pattern, re := range patterns {
+		if re.MatchString(key) {
+			matching = append(matching, pattern)
+		}
+	}
+
+	sort.Strings(matching)
+
+	for _, pattern := range matching {
+		props = append(props, s.PatternProperties[pattern])
+	}
+
+	return props
+}
+
+func matchAny(patterns map[string]*regexp.Regexp, key string) bool {
+	for _, re := range patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check returns an error if the environment value s does not match the schema.
+func (s *JSONSchema) check(val string) error {
+	switch s.Type {
+	case "array":
+		if s.Items == nil {
+			return nil
+		}
+
+		separator := s.Separator
+		if separator == "" {
+			separator = ","
+		}
+
+		slice, err := parseSliceOptions(s.Slice)
+		if err != nil {
+			return err
+		}
+
+		parts, err := splitSlice(val, separator, slice)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+
+		for i, part := range parts {
+			if err := s.Items.check(part); err != nil {
+				errs = append(errs, &ElementError{Index: i, Value: part, Err: err})
+			}
+		}
+
+		return errors.Join(errs...)
+	case "integer":
+		base := 10
+		if s.Base != nil {
+			base = *s.Base
+		}
+
+		n, ok := new(big.Int).SetString(val, base)
+		if !ok {
+			return fmt.Errorf("%q is not an integer", val)
+		}
+
+		return s.checkNumber(val, new(big.Float).SetInt(n))
+	case "number":
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+
+		return s.checkNumber(val, big.NewFloat(f))
+	case "boolean":
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+
+		return s.checkEnum(val, func(e any) bool { return e == b })
+	case "string":
+		switch s.Format {
+		case "go-duration":
+			return s.checkDuration(val)
+		case "uri-reference":
+			if _, err := parseURL(val); err != nil {
+				return err
+			}
+		}
+
+		return s.checkEnum(val, func(e any) bool { return e == val })
+	default:
+		return nil
+	}
+}
+
+func (s *JSONSchema) checkNumber(val string, n *big.Float) error {
+	if min, ok := parseNumber(s.Minimum); ok && n.Cmp(min) < 0 {
+		return fmt.Errorf("%s is less than the minimum %s", val, s.Minimum)
+	}
+
+	if max, ok := parseNumber(s.Maximum); ok && n.Cmp(max) > 0 {
+		return fmt.Errorf("%s is greater than the maximum %s", val, s.Maximum)
+	}
+
+	return s.checkEnum(val, func(e any) bool {
+		f, ok := parseNumber(e)
+		return ok && n.Cmp(f) == 0
+	})
+}
+
+func (s *JSONSchema) checkDuration(val string) error {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return err
+	}
+
+	if min, err := time.ParseDuration(s.FormatMinimum); err == nil && d < min {
+		return fmt.Errorf("%s is less than the minimum %s", val, s.FormatMinimum)
+	}
+
+	if max, err := time.ParseDuration(s.FormatMaximum); err == nil && d > max {
+		return fmt.Errorf("%s is greater than the maximum %s", val, s.FormatMaximum)
+	}
+
+	return s.checkEnum(val, func(e any) bool {
+		str, ok := e.(string)
+		if !ok {
+			return false
+		}
+
+		allowed, err := time.ParseDuration(str)
+
+		return err == nil && allowed == d
+	})
+}
+
+func (s *JSONSchema) checkEnum(val string, equal func(any) bool) error {
+	if len(s.Enum) == 0 {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(s.Enum))
+	for _, e := range s.Enum {
+		if equal(e) {
+			return nil
+		}
+
+		allowed = append(allowed, strconv.Quote(fmt.Sprint(e)))
+	}
+
+	return fmt.Errorf("%q is not one of %s", val, strings.Join(allowed, ", "))
+}
+
+// parseNumber parses a number of a schema, which is a [json.Number] or a float64 depending on
+// how the schema was decoded.
+func parseNumber(v any) (*big.Float, bool) {
+	var s string
+
+	switch n := v.(type) {
+	case json.Number:
+		s = string(n)
+	case float64:
+		return big.NewFloat(n), true
+	default:
+		return nil, false
+	}
+
+	f, ok := new(big.Float).SetPrec(128).SetString(s)
+
+	return f, ok
+}
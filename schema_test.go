@@ -0,0 +1,223 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaConfig struct {
+	Port     uint16          `env:"PORT,required" envMin:"1024" envUsage:"listen port"`
+	Level    string          `env:"LEVEL" envEnum:"debug,info" envDefault:"info"`
+	Mask     int8            `env:"MASK" envBase:"16"`
+	Timeout  time.Duration   `env:"TIMEOUT" envMax:"1m" envDefault:"5s"`
+	Retries  []int           `env:"RETRIES" envSeparator:";" envMax:"5" envDefault:"1;2"`
+	Password string          `env:"PASSWORD,required,secret" envDefault:"changeme"`
+	Peers    []time.Duration `env:"PEERS"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema, err := SchemaOf((*schemaConfig)(nil))
+	if err != nil {
+		t.Fatalf("schema failed %s", err.Error())
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"LEVEL":{"type":"string","default":"info","enum":["debug","info"]},` +
+		`"MASK":{"type":"integer","minimum":-128,"maximum":127,"x-env-base":16},` +
+		`"PASSWORD":{"type":"string","default":"changeme","writeOnly":true},` +
+		`"PEERS":{"type":"array","items":{"type":"string","format":"go-duration"},"x-env-separator":","},` +
+		`"PORT":{"type":"integer","description":"listen port","minimum":1024,"maximum":65535},` +
+		`"RETRIES":{"type":"array","default":[1,2],"items":{"type":"integer","minimum":-9223372036854775808,"maximum":5},"x-env-separator":";"},` +
+		`"TIMEOUT":{"type":"string","format":"go-duration","default":"5s","formatMaximum":"1m0s"}},` +
+		`"required":["PORT"]}`
+	if string(b) != expected {
+		t.Errorf("expected %s got %s", expected, b)
+	}
+
+	if _, err := SchemaOf(42); err == nil {
+		t.Errorf("expected error for non struct")
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := SchemaOf(schemaConfig{})
+	if err != nil {
+		t.Fatalf("schema failed %s", err.Error())
+	}
+
+	// Validate a schema that went through JSON, as a deployment tool would.
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	var decoded JSONSchema
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	valid := map[string]string{"PORT": "8080", "LEVEL": "debug", "MASK": "-7f", "TIMEOUT": "30s", "RETRIES": "0;5", "OTHER": "x"}
+	if err := decoded.Validate(valid); err != nil {
+		t.Errorf("expected valid values got %s", err.Error())
+	}
+
+	err = decoded.Validate(map[string]string{
+		"LEVEL":   "trace",
+		"MASK":    "80",
+		"TIMEOUT": "2m",
+		"RETRIES": "1;6",
+		"PEERS":   "1s,soon",
+	})
+
+	expected := []string{
+		`env: invalid value for LEVEL: "trace" is not one of "debug", "info"`,
+		`env: invalid value for MASK: 80 is greater than the maximum 127`,
+		`env: invalid value for PEERS: element 1: time: invalid duration "soon"`,
+		`env: required variable PORT is not set`,
+		`env: invalid value for RETRIES: element 1: 6 is greater than the maximum 5`,
+		`env: invalid value for TIMEOUT: 2m is greater than the maximum 1m0s`,
+	}
+	if err == nil || err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("expected %q got %v", strings.Join(expected, "\n"), err)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "LEVEL" {
+		t.Errorf("expected parse error for LEVEL got %v", err)
+	}
+}
+
+func TestUnmarshalConstraints(t *testing.T) {
+	var cfg schemaConfig
+
+	err := UnmarshalFrom(NewMap(map[string]string{"PORT": "80", "LEVEL": "trace", "RETRIES": "1;9"}), &cfg)
+
+	expected := "env: invalid value for PORT: 80 is less than the minimum 1024\n" +
+		"env: invalid value for LEVEL: \"trace\" is not one of \"debug\", \"info\"\n" +
+		"env: invalid value for RETRIES: element 1: 9 is greater than the maximum 5"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q got %v", expected, err)
+	}
+
+	var invalid struct {
+		Name string `env:"NAME" envMin:"a"`
+	}
+
+	if err := UnmarshalFrom(NewMap(nil), &invalid); err == nil {
+		t.Errorf("expected error for envMin on a string")
+	}
+}
+
+func TestRegistrySchema(t *testing.T) {
+	isolateRegistry(t)
+
+	DurationVar("KEY_SCHEMA_TIMEOUT", time.Second, "timeout").MarkRequired()
+	IntSliceVar[int]("KEY_SCHEMA_PORTS", " ", 0, nil, "ports")
+	StringVar("KEY_SCHEMA_TOKEN", "", "token").MarkSecret()
+
+	schema := RegistrySchema()
+
+	timeout := schema.Properties["KEY_SCHEMA_TIMEOUT"]
+	if timeout == nil || timeout.Format != "go-duration" || timeout.Default != "1s" || timeout.Description != "timeout" {
+		t.Errorf("unexpected timeout schema %+v", timeout)
+	}
+
+	ports := schema.Properties["KEY_SCHEMA_PORTS"]
+	if ports == nil || ports.Type != "array" || ports.Separator != " " || ports.Default != nil || ports.Items.Base == nil || *ports.Items.Base != 0 {
+		t.Errorf("unexpected ports schema %+v", ports)
+	}
+
+	if token := schema.Properties["KEY_SCHEMA_TOKEN"]; token == nil || !token.WriteOnly {
+		t.Errorf("expected token to be write only got %+v", token)
+	}
+
+	if err := equalSlices(schema.Required, []string{"KEY_SCHEMA_TIMEOUT"}); err != nil {
+		t.Errorf("expected required variables [KEY_SCHEMA_TIMEOUT] got %v", schema.Required)
+	}
+
+	if err := schema.Validate(map[string]string{"KEY_SCHEMA_TIMEOUT": "1s", "KEY_SCHEMA_PORTS": "0x50 443"}); err != nil {
+		t.Errorf("expected valid ports got %s", err.Error())
+	}
+}
+
+func TestRegistrySchemaInvalidDefault(t *testing.T) {
+	isolateRegistry(t)
+
+	URLVar("KEY_SCHEMA_UPSTREAM", url.URL{}, "upstream")
+
+	upstream := RegistrySchema().Properties["KEY_SCHEMA_UPSTREAM"]
+	if upstream == nil || upstream.Default != nil || upstream.Description != "upstream" {
+		t.Errorf("expected upstream without default got %+v", upstream)
+	}
+}
+
+func TestSchemaIndexed(t *testing.T) {
+	type config struct {
+		Hosts     []string   `env:"HOSTS,required" envIndexed:"_"`
+		Upstreams []upstream `envPrefix:"UPSTREAM_"`
+	}
+
+	schema, err := SchemaOf(config{})
+	if err != nil {
+		t.Fatalf("schema failed %s", err.Error())
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","patternProperties":{` +
+		`"^HOSTS_[0-9]+$":{"type":"string"},` +
+		`"^UPSTREAM_[0-9]+_URL$":{"type":"string","format":"uri-reference"},` +
+		`"^UPSTREAM_[0-9]+_WEIGHT$":{"type":"integer","default":1,"minimum":-9223372036854775808,"maximum":9223372036854775807}},` +
+		`"required":["HOSTS_0"]}`
+	if string(b) != expected {
+		t.Errorf("expected %s got %s", expected, b)
+	}
+
+	err = schema.Validate(map[string]string{"HOSTS_0": "a", "UPSTREAM_0_URL": "http://a", "UPSTREAM_1_WEIGHT": "x", "OTHER": "x"})
+
+	expectedErr := `env: invalid value for UPSTREAM_1_WEIGHT: "x" is not an integer`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected %q got %v", expectedErr, err)
+	}
+}
+
+func TestSchemaMap(t *testing.T) {
+	schema, err := SchemaOf(mapConfig{})
+	if err != nil {
+		t.Fatalf("schema failed %s", err.Error())
+	}
+
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","patternProperties":{` +
+		`"^CACHE__.+__SIZE$":{"type":"integer","default":100,"minimum":-9223372036854775808,"maximum":9223372036854775807},` +
+		`"^CACHE__.+__TTL$":{"type":"string","format":"go-duration"},` +
+		`"^LIMIT_[^_]+$":{"type":"integer","minimum":-9223372036854775808,"maximum":50},` +
+		`"^TAG\\.[^\\.]+$":{"type":"array","items":{"type":"string"},"x-env-separator":","}}}`
+	if string(b) != expected {
+		t.Errorf("expected %s got %s", expected, b)
+	}
+
+	err = schema.Validate(map[string]string{"LIMIT_USERS": "60", "CACHE__USERS__TTL": "5m", "CACHE__USERS__SIZE": "x"})
+
+	expectedErr := `env: invalid value for CACHE__USERS__SIZE: "x" is not an integer` + "\n" +
+		`env: invalid value for LIMIT_USERS: 60 is greater than the maximum 50`
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("expected %q got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,117 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shell is a shell [WriteExports] can render variables for.
+type Shell string
+
+const (
+	// ShellPOSIX renders export KEY='value' lines for sh, bash, zsh and other POSIX shells.
+	ShellPOSIX Shell = "sh"
+	// ShellFish renders set -gx KEY 'value' lines for fish.
+	ShellFish Shell = "fish"
+	// ShellPowerShell renders $env:KEY = 'value' lines for PowerShell.
+	ShellPowerShell Shell = "powershell"
+	// ShellCmd renders set KEY=value lines for Windows cmd.exe batch files.
+	ShellCmd Shell = "cmd"
+)
+
+// WriteExports writes the variables, in the KEY=value form returned by [Marshal] and
+// [os.Environ], to w as a script that sets them when evaluated by the shell, e.g. with
+// eval "$(app -print-env)". Values are quoted so that spaces, quotes, newlines and dollar
+// signs are taken literally, and values of variables marked as secret with [Var.MarkSecret],
+// or bound to struct fields tagged as secret, are redacted.
+//
+// Keys must be valid shell identifiers. Windows cmd.exe can not set values containing
+// newlines, which are reported as errors for [ShellCmd].
+func WriteExports(w io.Writer, shell Shell, vars []string) error {
+	var b strings.Builder
+
+	for _, kv := range vars {
+		key, val, ok := strings.Cut(kv, "=")
+		if !ok || !validShellKey(key) {
+			return fmt.Errorf("env: invalid variable %q", kv)
+		}
+
+		if isSecret(key) {
+			val = redacted
+		}
+
+		switch shell {
+		case ShellPOSIX:
+			fmt.Fprintf(&b, "export %s=%s\n", key, posixQuote(val))
+		case ShellFish:
+			fmt.Fprintf(&b, "set -gx %s %s\n", key, fishQuote(val))
+		case ShellPowerShell:
+			fmt.Fprintf(&b, "$env:%s = %s\n", key, powerShellQuote(val))
+		case ShellCmd:
+			if strings.ContainsAny(val, "\r\n") {
+				return fmt.Errorf("env: value of %s contains a newline, which cmd can not set", key)
+			}
+
+			fmt.Fprintf(&b, "set %s=%s\n", key, cmdEscape(val))
+		default:
+			return fmt.Errorf("env: unknown shell %q", shell)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// validShellKey reports whether the key is a name every supported shell accepts unquoted.
+func validShellKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, r := range key {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// posixQuote single quotes s, inside which POSIX shells expand nothing. A single quote is
+// written by closing the quotes, writing an escaped quote and reopening them.
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single quotes s, inside which fish only treats \\ and \' as escapes.
+func fishQuote(s string) string {
+	return "'" + fishQuotes.Replace(s) + "'"
+}
+
+var fishQuotes = strings.NewReplacer(`\`, `\\`, "'", `\'`)
+
+// powerShellQuote single quotes s, inside which PowerShell expands nothing and a single
+// quote is written doubled. PowerShell also accepts the typographic single quotes as quotes,
+// so those are doubled as well.
+func powerShellQuote(s string) string {
+	return "'" + powerShellQuotes.Replace(s) + "'"
+}
+
+var powerShellQuotes = strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019",
+	"\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b")
+
+// cmdEscape escapes the characters cmd.exe treats specially in a batch file with a caret, and
+// percent signs by doubling them.
+func cmdEscape(s string) string {
+	return cmdSpecials.Replace(s)
+}
+
+var cmdSpecials = strings.NewReplacer(
+	"^", "^^", "&", "^&", "|", "^|", "<", "^<", ">", "^>",
+	"(", "^(", ")", "^)", `"`, `^"`, "%", "%%",
+)
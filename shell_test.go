@@ -0,0 +1,80 @@
+package env
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWriteExports(t *testing.T) {
+	isolateRegistry(t)
+
+	StringVar("KEY_SHELL_TOKEN", "", "API token").MarkSecret()
+
+	vars := []string{"KEY_SHELL_VALUE=it's \"$HOME\" 100% & more", "KEY_SHELL_TOKEN=hunter2"}
+
+	tests := []struct {
+		shell    Shell
+		expected string
+	}{
+		{ShellPOSIX, "export KEY_SHELL_VALUE='it'\\''s \"$HOME\" 100% & more'\nexport KEY_SHELL_TOKEN='******'\n"},
+		{ShellFish, "set -gx KEY_SHELL_VALUE 'it\\'s \"$HOME\" 100% & more'\nset -gx KEY_SHELL_TOKEN '******'\n"},
+		{ShellPowerShell, "$env:KEY_SHELL_VALUE = 'it''s \"$HOME\" 100% & more'\n$env:KEY_SHELL_TOKEN = '******'\n"},
+		{ShellCmd, "set KEY_SHELL_VALUE=it's ^\"$HOME^\" 100%% ^& more\nset KEY_SHELL_TOKEN=******\n"},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := WriteExports(&b, test.shell, vars); err != nil {
+			t.Errorf("write exports for %s failed %s", test.shell, err.Error())
+		}
+
+		if b.String() != test.expected {
+			t.Errorf("expected %s exports %q got %q", test.shell, test.expected, b.String())
+		}
+	}
+}
+
+func TestWriteExportsPOSIXEval(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	value := "a 'quoted' \"value\"\nwith $HOME, `cmd` and \\n"
+
+	var b bytes.Buffer
+	if err := WriteExports(&b, ShellPOSIX, []string{"KEY_SHELL_EVAL=" + value}); err != nil {
+		t.Fatalf("write exports failed %s", err.Error())
+	}
+
+	out, err := exec.Command(sh, "-c", b.String()+`printf %s "$KEY_SHELL_EVAL"`).Output()
+	if err != nil {
+		t.Fatalf("eval failed %s", err.Error())
+	}
+
+	if string(out) != value {
+		t.Errorf("expected %q got %q", value, out)
+	}
+}
+
+func TestWriteExportsErrors(t *testing.T) {
+	tests := []struct {
+		shell Shell
+		vars  []string
+	}{
+		{ShellPOSIX, []string{"INVALID"}},
+		{ShellPOSIX, []string{"1KEY=value"}},
+		{ShellPOSIX, []string{"KEY-NAME=value"}},
+		{ShellCmd, []string{"KEY=multi\nline"}},
+		{Shell("tcsh"), []string{"KEY=value"}},
+	}
+
+	for _, test := range tests {
+		err := WriteExports(&bytes.Buffer{}, test.shell, test.vars)
+		if err == nil || !strings.HasPrefix(err.Error(), "env: ") {
+			t.Errorf("expected error for %s %q got %v", test.shell, test.vars, err)
+		}
+	}
+}
@@ -0,0 +1,468 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type sliceOptions struct {
+	trimSpace   bool
+	dropEmpty   bool
+	quoted      bool
+	escaped     bool
+	skipInvalid bool
+	indexed     *indexedKeys
+}
+
+// indexedKeys names the variables holding the elements of an indexed slice, see
+// [SliceIndexed].
+type indexedKeys struct {
+	separator string
+	start     int
+}
+
+// key returns the name of the variable holding the element of the slice named by the key
+// at index i.
+func (k indexedKeys) key(key string, i int) string {
+	return key + k.separator + strconv.Itoa(i)
+}
+
+// SliceOption configures how the slice getters split a value into elements. By default the
+// value is split at every occurrence of the separator and the elements are used unchanged.
+type SliceOption func(*sliceOptions)
+
+// SliceTrimSpace removes leading and trailing white space from the elements, so that
+// "a, b, c" is split into a, b and c.
+func SliceTrimSpace() SliceOption {
+	return func(o *sliceOptions) {
+		o.trimSpace = true
+	}
+}
+
+// SliceDropEmpty drops empty elements, so that "a,,b" is split into a and b and an empty
+// value into an empty slice. Elements quoted with [SliceQuoted] are kept even if empty.
+func SliceDropEmpty() SliceOption {
+	return func(o *sliceOptions) {
+		o.dropEmpty = true
+	}
+}
+
+// SliceQuoted reads elements enclosed in double quotes as in CSV, so that they can contain
+// the separator: "\"a,b\",c" is split into a,b and c. A double quote inside a quoted element
+// is written twice. Double quotes inside unquoted elements are taken literally.
+func SliceQuoted() SliceOption {
+	return func(o *sliceOptions) {
+		o.quoted = true
+	}
+}
+
+// SliceEscaped reads a backslash as an escape of the following character, so that elements
+// can contain the separator: "a\\,b,c" is split into a,b and c. A backslash is written twice.
+func SliceEscaped() SliceOption {
+	return func(o *sliceOptions) {
+		o.escaped = true
+	}
+}
+
+// SliceSkipInvalid skips the elements that could not be parsed instead of rejecting the whole
+// value, so that the valid elements are returned rather than the default value. The skipped
+// elements are still reported, see [ElementError].
+func SliceSkipInvalid() SliceOption {
+	return func(o *sliceOptions) {
+		o.skipInvalid = true
+	}
+}
+
+// SliceIndexed reads the elements of a slice from one variable each, named by the key
+// followed by the separator and the index of the element, from start up to the first
+// missing index: with SliceIndexed("_", 0) the elements of HOSTS are read from HOSTS_0,
+// HOSTS_1 and so on, and with SliceIndexed("__", 1) from HOSTS__1, HOSTS__2 and so on. The
+// variable named by the key itself is not read.
+//
+// The separator passed to the getter is only used to split the value of a flag, see
+// [StringSliceFlag], and the default value of a declared variable, see [StringSliceVar],
+// whose elements are escaped as with [SliceEscaped].
+func SliceIndexed(separator string, start int) SliceOption {
+	return func(o *sliceOptions) {
+		o.indexed = &indexedKeys{separator: separator, start: start}
+	}
+}
+
+func newSliceOptions(opts []SliceOption) sliceOptions {
+	var o sliceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// sliceOptionNames are the names of the slice options in the envSlice tag, see [Unmarshal],
+// and the x-env-slice keyword of a [JSONSchema].
+var sliceOptionNames = []struct {
+	name string
+	opt  SliceOption
+	set  func(sliceOptions) bool
+}{
+	{"trimspace", SliceTrimSpace(), func(o sliceOptions) bool { return o.trimSpace }},
+	{"dropempty", SliceDropEmpty(), func(o sliceOptions) bool { return o.dropEmpty }},
+	{"quoted", SliceQuoted(), func(o sliceOptions) bool { return o.quoted }},
+	{"escaped", SliceEscaped(), func(o sliceOptions) bool { return o.escaped }},
+	{"skipinvalid", SliceSkipInvalid(), func(o sliceOptions) bool { return o.skipInvalid }},
+}
+
+// parseSliceOptions returns the slice options named by names.
+func parseSliceOptions(names []string) (sliceOptions, error) {
+	var o sliceOptions
+
+	for _, name := range names {
+		found := false
+
+		for _, n := range sliceOptionNames {
+			if n.name == name {
+				n.opt(&o)
+				found = true
+			}
+		}
+
+		if !found {
+			return sliceOptions{}, fmt.Errorf("unknown slice option %q", name)
+		}
+	}
+
+	return o, nil
+}
+
+// names returns the names of the options that are set.
+func (o sliceOptions) names() []string {
+	var names []string
+
+	for _, n := range sliceOptionNames {
+		if n.set(o) {
+			names = append(names, n.name)
+		}
+	}
+
+	return names
+}
+
+// ElementError is returned for every element of a slice that could not be parsed. The errors
+// of the elements of a value are joined into the Err of its [*ParseError], and can be listed
+// with [ElementErrors].
+type ElementError struct {
+	// Index is the position of the element in the value, once empty elements are dropped with
+	// [SliceDropEmpty], or its index for a slice read with [SliceIndexed].
+	Index int
+	// Value is the raw text of the element.
+	Value string
+	// Err is the error returned by the parser.
+	Err error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("element %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// ElementErrors returns every [*ElementError] in the tree of err, in the order of their
+// elements.
+func ElementErrors(err error) []*ElementError {
+	var errs []*ElementError
+
+	switch e := err.(type) {
+	case nil:
+	case *ElementError:
+		errs = append(errs, e)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			errs = append(errs, ElementErrors(err)...)
+		}
+	case interface{ Unwrap() error }:
+		errs = ElementErrors(e.Unwrap())
+	}
+
+	return errs
+}
+
+// skippedError wraps the errors of the elements skipped with [SliceSkipInvalid], so that the
+// valid elements parsed along with it are used.
+type skippedError struct {
+	err error
+}
+
+func (e *skippedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *skippedError) Unwrap() error {
+	return e.err
+}
+
+// elementIndex returns the index of the element at position i of a slice, which is offset by
+// the start of an indexed slice.
+func (o sliceOptions) elementIndex(i int) int {
+	if o.indexed != nil {
+		return i + o.indexed.start
+	}
+
+	return i
+}
+
+// elementsError joins the errors of the elements of a value, marking them as skipped if
+// invalid elements are skipped.
+func (o sliceOptions) elementsError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	err := errors.Join(errs...)
+	if o.skipInvalid {
+		return &skippedError{err: err}
+	}
+
+	return err
+}
+
+var (
+	errUnterminatedQuote = errors.New("unterminated quoted element")
+	errAfterQuote        = errors.New("unexpected characters after quoted element")
+	errTrailingEscape    = errors.New("unterminated escape at end of value")
+)
+
+// splitSlice splits s into its elements at the separator. The elements of an indexed slice
+// are escaped, as joined by [joinSlice].
+func splitSlice(s, separator string, o sliceOptions) ([]string, error) {
+	if o.indexed != nil {
+		o.quoted, o.escaped = false, true
+	}
+
+	if separator == "" || (!o.quoted && !o.escaped) {
+		return o.clean(strings.Split(s, separator)), nil
+	}
+
+	var (
+		elems []string
+		b     strings.Builder
+	)
+
+	for i := 0; ; {
+		b.Reset()
+
+		start := i
+		if o.trimSpace {
+			start += len(s[i:]) - len(strings.TrimLeft(s[i:], " \t\r\n"))
+		}
+
+		if o.quoted && start < len(s) && s[start] == '"' {
+			end, err := readQuoted(&b, s, start+1, o.escaped)
+			if err != nil {
+				return nil, err
+			}
+
+			if o.trimSpace {
+				end += len(s[end:]) - len(strings.TrimLeft(s[end:], " \t\r\n"))
+			}
+
+			if end < len(s) && !strings.HasPrefix(s[end:], separator) {
+				return nil, errAfterQuote
+			}
+
+			elems = append(elems, b.String())
+
+			if end == len(s) {
+				return elems, nil
+			}
+
+			i = end + len(separator)
+
+			continue
+		}
+
+		for i < len(s) && !strings.HasPrefix(s[i:], separator) {
+			if o.escaped && s[i] == '\\' {
+				if i+1 == len(s) {
+					return nil, errTrailingEscape
+				}
+
+				i++
+			}
+
+			b.WriteByte(s[i])
+			i++
+		}
+
+		elems = append(elems, o.clean([]string{b.String()})...)
+
+		if i == len(s) {
+			return elems, nil
+		}
+
+		i += len(separator)
+	}
+}
+
+// readQuoted reads the quoted element of s starting at i, just after the opening quote, into
+// b and returns the index following the closing quote.
+func readQuoted(b *strings.Builder, s string, i int, escaped bool) (int, error) {
+	for i < len(s) {
+		switch {
+		case escaped && s[i] == '\\':
+			if i+1 == len(s) {
+				return 0, errTrailingEscape
+			}
+
+			b.WriteByte(s[i+1])
+			i += 2
+		case s[i] == '"' && i+1 < len(s) && s[i+1] == '"':
+			b.WriteByte('"')
+			i += 2
+		case s[i] == '"':
+			return i + 1, nil
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return 0, errUnterminatedQuote
+}
+
+// clean trims and drops the unquoted elements as configured.
+func (o sliceOptions) clean(elems []string) []string {
+	if !o.trimSpace && !o.dropEmpty {
+		return elems
+	}
+
+	cleaned := elems[:0]
+
+	for _, elem := range elems {
+		if o.trimSpace {
+			elem = strings.TrimSpace(elem)
+		}
+
+		if elem != "" || !o.dropEmpty {
+			cleaned = append(cleaned, elem)
+		}
+	}
+
+	return cleaned
+}
+
+// joinSlice joins the formatted elements with the separator, quoting or escaping them as
+// configured so that splitSlice returns them unchanged.
+func joinSlice(elems []string, separator string, o sliceOptions) string {
+	if o.indexed != nil {
+		o.quoted, o.escaped = false, true
+	}
+
+	if separator == "" || (!o.quoted && !o.escaped) {
+		return strings.Join(elems, separator)
+	}
+
+	joined := make([]string, 0, len(elems))
+
+	for _, elem := range elems {
+		if o.escaped {
+			elem = strings.ReplaceAll(elem, `\`, `\\`)
+		}
+
+		switch {
+		case o.quoted && needsQuotes(elem, separator, o):
+			elem = `"` + strings.ReplaceAll(elem, `"`, `""`) + `"`
+		case o.escaped:
+			elem = strings.ReplaceAll(elem, separator, `\`+strings.Join(strings.Split(separator, ""), `\`))
+		}
+
+		joined = append(joined, elem)
+	}
+
+	return strings.Join(joined, separator)
+}
+
+func needsQuotes(elem, separator string, o sliceOptions) bool {
+	return strings.Contains(elem, separator) ||
+		strings.HasPrefix(strings.TrimSpace(elem), `"`) ||
+		(o.trimSpace && strings.TrimSpace(elem) != elem) ||
+		(o.dropEmpty && elem == "")
+}
+
+// bindSlice returns the source and the parser resolving a slice configured by o through src.
+// Values are parsed with parse, unless the slice is indexed: its elements are then gathered
+// from their variables by the returned source and parsed with parseElems as they are, rather
+// than joined into one value and split again, which would not preserve them for every
+// separator.
+func bindSlice[V any](o sliceOptions, src Source, separator string, parse func(string) (V, error), parseElems func([]string) (V, error)) (Source, func(string) (V, error)) {
+	if o.indexed == nil {
+		return src, parse
+	}
+
+	s := &indexedSource{src: src, separator: separator, keys: *o.indexed}
+
+	return s, func(string) (V, error) {
+		return parseElems(o.clean(s.elems))
+	}
+}
+
+// indexedSource is a [Source] gathering the elements of the indexed slice named by a key. The
+// elements of the first slice found are kept for the parser returned by [bindSlice], which is
+// the slice the value is resolved from since aliases are only looked up when the key is not
+// present. Lookup returns them joined with the separator, for reports and logs only.
+type indexedSource struct {
+	src       Source
+	separator string
+	keys      indexedKeys
+	elems     []string
+	found     bool
+}
+
+func (s *indexedSource) Lookup(key string) (string, bool) {
+	var elems []string
+
+	for i := s.keys.start; ; i++ {
+		elemKey := s.keys.key(key, i)
+		recordRead(elemKey)
+
+		val, ok := s.src.Lookup(elemKey)
+		if !ok {
+			break
+		}
+
+		elems = append(elems, val)
+	}
+
+	if len(elems) == 0 {
+		return "", false
+	}
+
+	if !s.found {
+		s.elems, s.found = elems, true
+	}
+
+	return strings.Join(elems, s.separator), true
+}
+
+func (s *indexedSource) Keys() []string {
+	return s.src.Keys()
+}
+
+// Provenance returns the provenance of the first element of the slice named by the key.
+func (s *indexedSource) Provenance(key string) Provenance {
+	return provenanceOf(s.src, s.keys.key(key, s.keys.start))
+}
+
+func (s *indexedSource) observeMissing(key string) {
+	if o, ok := s.src.(lookupObserver); ok {
+		o.observeMissing(key)
+	}
+}
+
+func (s *indexedSource) observeParseError(err *ParseError) {
+	if o, ok := s.src.(lookupObserver); ok {
+		o.observeParseError(err)
+	}
+}
@@ -0,0 +1,283 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitSlice(t *testing.T) {
+	tests := []struct {
+		value    string
+		opts     []SliceOption
+		expected []string
+	}{
+		{"a, b ,c", nil, []string{"a", " b ", "c"}},
+		{"a, b ,c", []SliceOption{SliceTrimSpace()}, []string{"a", "b", "c"}},
+		{"a,,b,", []SliceOption{SliceDropEmpty()}, []string{"a", "b"}},
+		{"", []SliceOption{SliceDropEmpty()}, []string{}},
+		{"a, ,b", []SliceOption{SliceTrimSpace(), SliceDropEmpty()}, []string{"a", "b"}},
+		{`"a,b",c`, []SliceOption{SliceQuoted()}, []string{"a,b", "c"}},
+		{`"say ""hi""",a"b`, []SliceOption{SliceQuoted()}, []string{`say "hi"`, `a"b`}},
+		{` " a " , b`, []SliceOption{SliceQuoted(), SliceTrimSpace()}, []string{" a ", "b"}},
+		{`"",,a`, []SliceOption{SliceQuoted(), SliceDropEmpty()}, []string{"", "a"}},
+		{`a\,b,c\\`, []SliceOption{SliceEscaped()}, []string{"a,b", `c\`}},
+		{`"a\"b",c`, []SliceOption{SliceQuoted(), SliceEscaped()}, []string{`a"b`, "c"}},
+	}
+
+	for _, test := range tests {
+		got, err := splitSlice(test.value, ",", newSliceOptions(test.opts))
+		if err != nil {
+			t.Errorf("expected %q to split got %s", test.value, err.Error())
+			continue
+		}
+
+		if err := equalSlices(got, test.expected); err != nil {
+			t.Errorf("expected %q to split into %q %s", test.value, test.expected, err.Error())
+		}
+	}
+}
+
+func TestSplitSliceErrors(t *testing.T) {
+	tests := []struct {
+		value    string
+		opts     []SliceOption
+		expected error
+	}{
+		{`"a,b`, []SliceOption{SliceQuoted()}, errUnterminatedQuote},
+		{`"a"b,c`, []SliceOption{SliceQuoted()}, errAfterQuote},
+		{`a,b\`, []SliceOption{SliceEscaped()}, errTrailingEscape},
+	}
+
+	for _, test := range tests {
+		if _, err := splitSlice(test.value, ",", newSliceOptions(test.opts)); err != test.expected {
+			t.Errorf("expected %q to fail with %v got %v", test.value, test.expected, err)
+		}
+	}
+}
+
+func TestJoinSlice(t *testing.T) {
+	elems := []string{"a,b", `say "hi"`, `c:\dir`, " d ", ""}
+
+	for _, opts := range [][]SliceOption{
+		{SliceQuoted()},
+		{SliceEscaped()},
+		{SliceQuoted(), SliceEscaped()},
+		{SliceQuoted(), SliceTrimSpace(), SliceDropEmpty()},
+	} {
+		o := newSliceOptions(opts)
+
+		joined := joinSlice(elems, ",", o)
+
+		got, err := splitSlice(joined, ",", o)
+		if err != nil {
+			t.Errorf("expected %q to split got %s", joined, err.Error())
+			continue
+		}
+
+		if err := equalSlices(got, elems); err != nil {
+			t.Errorf("expected %q to split into the joined elements %s", joined, err.Error())
+		}
+	}
+}
+
+func TestGetSliceOptions(t *testing.T) {
+	t.Setenv("KEY_SLICE_OPTIONS", ` 1, 2,, 3 `)
+
+	ints := GetIntSlice("KEY_SLICE_OPTIONS", ",", 10, []int{0})
+	if err := equalSlices(ints, []int{0}); err != nil {
+		t.Errorf("expected default value without options %s", err.Error())
+	}
+
+	ints = GetIntSlice("KEY_SLICE_OPTIONS", ",", 10, []int{0}, SliceTrimSpace(), SliceDropEmpty())
+	if err := equalSlices(ints, []int{1, 2, 3}); err != nil {
+		t.Errorf("expected env var value %s", err.Error())
+	}
+
+	t.Setenv("KEY_SLICE_OPTIONS", `"a b" c`)
+
+	strs := GetStringSlice("KEY_SLICE_OPTIONS", " ", []string{}, SliceQuoted())
+	if err := equalSlices(strs, []string{"a b", "c"}); err != nil {
+		t.Errorf("expected env var value %s", err.Error())
+	}
+}
+
+func TestSliceVarOptions(t *testing.T) {
+	isolateRegistry(t)
+
+	v := StringSliceVar("KEY_SLICE_VAR_OPTIONS", ",", []string{"a,b", "c"}, "usage", SliceQuoted())
+	if v.Variable().Default != `"a,b",c` {
+		t.Errorf("expected quoted default got %q", v.Variable().Default)
+	}
+
+	t.Setenv("KEY_SLICE_VAR_OPTIONS", `"x,y",z`)
+
+	if err := equalSlices(v.Get(), []string{"x,y", "z"}); err != nil {
+		t.Errorf("expected env var value %s", err.Error())
+	}
+
+	schema, err := json.Marshal(RegistrySchema().Properties["KEY_SLICE_VAR_OPTIONS"])
+	if err != nil {
+		t.Fatalf("marshal failed %s", err.Error())
+	}
+
+	if !strings.Contains(string(schema), `"x-env-slice":["quoted"]`) {
+		t.Errorf("expected slice options in schema got %s", schema)
+	}
+}
+
+func TestUnmarshalSliceTag(t *testing.T) {
+	var cfg struct {
+		Hosts []string `env:"HOSTS" envSlice:"trimspace,dropempty"`
+	}
+
+	if err := UnmarshalFrom(NewMap(map[string]string{"HOSTS": "a, b,"}), &cfg); err != nil {
+		t.Fatalf("unmarshal failed %s", err.Error())
+	}
+
+	if err := equalSlices(cfg.Hosts, []string{"a", "b"}); err != nil {
+		t.Errorf("expected trimmed hosts %s", err.Error())
+	}
+
+	var invalid struct {
+		Hosts []string `env:"HOSTS" envSlice:"trim"`
+	}
+
+	err := UnmarshalFrom(NewMap(nil), &invalid)
+	if err == nil || err.Error() != `env: field Hosts has unknown slice option "trim"` {
+		t.Errorf("expected unknown slice option error got %v", err)
+	}
+}
+
+func TestSliceElementErrors(t *testing.T) {
+	isolateRegistry(t)
+
+	src := NewMap(map[string]string{"KEY_SLICE_PORTS": "80,8o8o,443,x"})
+
+	v := UintSliceVar[uint16]("KEY_SLICE_PORTS", ",", 10, []uint16{8080}, "")
+
+	val, err := v.Lookup(src)
+	if err := equalSlices(val, []uint16{8080}); err != nil {
+		t.Errorf("expected default value %s", err.Error())
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "KEY_SLICE_PORTS" {
+		t.Fatalf("expected parse error got %v", err)
+	}
+
+	elemErrs := ElementErrors(err)
+	if len(elemErrs) != 2 || elemErrs[0].Index != 1 || elemErrs[0].Value != "8o8o" || elemErrs[1].Index != 3 || elemErrs[1].Value != "x" {
+		t.Errorf("expected errors for elements 1 and 3 got %v", elemErrs)
+	}
+
+	skipping := UintSliceVar[uint16]("KEY_SLICE_PORTS_SKIP", ",", 10, []uint16{8080}, "", SliceSkipInvalid())
+
+	val, err = skipping.Lookup(NewMap(map[string]string{"KEY_SLICE_PORTS_SKIP": "80,8o8o,443,x"}))
+	if err := equalSlices(val, []uint16{80, 443}); err != nil {
+		t.Errorf("expected valid elements %s", err.Error())
+	}
+
+	if len(ElementErrors(err)) != 2 {
+		t.Errorf("expected the skipped elements to be reported got %v", err)
+	}
+}
+
+func TestUnmarshalSkipInvalid(t *testing.T) {
+	cfg := struct {
+		Retries []int `env:"RETRIES" envSlice:"skipinvalid" envMax:"5"`
+		Ports   []int `env:"PORTS"`
+	}{Ports: []int{8080}}
+
+	err := UnmarshalFrom(NewMap(map[string]string{"RETRIES": "1,9,x,3", "PORTS": "80,x"}), &cfg)
+
+	expected := "env: invalid value for RETRIES: element 1: 9 is greater than the maximum 5\n" +
+		"element 2: strconv.ParseInt: parsing \"x\": invalid syntax\n" +
+		"env: invalid value for PORTS: element 1: strconv.ParseInt: parsing \"x\": invalid syntax"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q got %v", expected, err)
+	}
+
+	if err := equalSlices(cfg.Retries, []int{1, 3}); err != nil {
+		t.Errorf("expected valid retries %s", err.Error())
+	}
+
+	if err := equalSlices(cfg.Ports, []int{8080}); err != nil {
+		t.Errorf("expected ports to keep their value %s", err.Error())
+	}
+}
+
+func TestLookupSlice(t *testing.T) {
+	t.Setenv("KEY_LOOKUP_PORTS", "80,443,8o8o")
+
+	ports, err := LookupUintSlice[uint16]("KEY_LOOKUP_PORTS", ",", 10)
+	if ports != nil {
+		t.Errorf("expected no ports got %v", ports)
+	}
+
+	elemErrs := ElementErrors(err)
+	if len(elemErrs) != 1 || elemErrs[0].Index != 2 || elemErrs[0].Value != "8o8o" {
+		t.Errorf("expected error for element 2 got %v", err)
+	}
+
+	ports, err = LookupUintSlice[uint16]("KEY_LOOKUP_PORTS", ",", 10, SliceSkipInvalid())
+	if err := equalSlices(ports, []uint16{80, 443}); err != nil {
+		t.Errorf("expected valid elements %s", err.Error())
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "KEY_LOOKUP_PORTS" || len(ElementErrors(err)) != 1 {
+		t.Errorf("expected the skipped element to be reported got %v", err)
+	}
+
+	hosts, err := LookupStringSliceFrom[string](NewMap(nil), "KEY_LOOKUP_HOSTS", ",")
+	if hosts != nil || err != nil {
+		t.Errorf("expected nil slice and error for a missing variable got %v %v", hosts, err)
+	}
+}
+
+func TestGetSliceIndexed(t *testing.T) {
+	src := NewMap(map[string]string{
+		"KEY_INDEXED_0":   "a,b",
+		"KEY_INDEXED_1":   `c\d`,
+		"KEY_INDEXED_3":   "after gap",
+		"KEY_INDEXED__1":  "80",
+		"KEY_INDEXED__2":  "8o8o",
+		"KEY_INDEXED__3":  "443",
+		"KEY_INDEXED_ALL": "x,y",
+	})
+
+	strs := GetStringSliceFrom(src, "KEY_INDEXED", ",", []string{"default"}, SliceIndexed("_", 0))
+	if err := equalSlices(strs, []string{"a,b", `c\d`}); err != nil {
+		t.Errorf("expected elements up to the first gap %s", err.Error())
+	}
+
+	strs = GetStringSliceFrom[string](src, "KEY_INDEXED", "", nil, SliceIndexed("_", 0))
+	if err := equalSlices(strs, []string{"a,b", `c\d`}); err != nil {
+		t.Errorf("expected elements without separator %s", err.Error())
+	}
+
+	strs = GetStringSliceFrom(src, "KEY_INDEXED_ALL", ",", []string{"default"}, SliceIndexed("_", 0))
+	if err := equalSlices(strs, []string{"default"}); err != nil {
+		t.Errorf("expected default value without elements %s", err.Error())
+	}
+
+	isolateRegistry(t)
+
+	v := UintSliceVar[uint16]("KEY_INDEXED", ",", 10, []uint16{8080}, "", SliceIndexed("__", 1), SliceSkipInvalid())
+
+	ports, err := v.Lookup(src)
+	if err := equalSlices(ports, []uint16{80, 443}); err != nil {
+		t.Errorf("expected valid elements %s", err.Error())
+	}
+
+	elemErrs := ElementErrors(err)
+	if len(elemErrs) != 1 || elemErrs[0].Index != 2 || elemErrs[0].Value != "8o8o" {
+		t.Errorf("expected error for element 2 got %v", err)
+	}
+
+	if p := provenanceFor("KEY_INDEXED"); p.Source != "map" {
+		t.Errorf("expected provenance of the first element got %+v", p)
+	}
+}
@@ -0,0 +1,123 @@
+package env
+
+import "sort"
+
+// ProvenanceMap is recorded for values read from a [Map] created with [NewMap].
+const ProvenanceMap = "map"
+
+// Map is an immutable [Source] backed by a map. Reads do not take any locks, so a Map can be
+// shared freely between goroutines. Unless reads are tracked or logged, see [SetTracking] and
+// [SetLogger], or aliases are registered, the *From getters parse the values of a Map
+// directly, skipping the bookkeeping of other sources.
+type Map struct {
+	keys        []string
+	values      map[string]string
+	provenances map[string]Provenance
+}
+
+// NewMap returns a [Map] holding a copy of the values.
+func NewMap(values map[string]string) *Map {
+	return newMap(values, ProvenanceMap)
+}
+
+// newMap returns a [Map] holding a copy of the values, reporting source as their provenance.
+func newMap(values map[string]string, source string) *Map {
+	m := &Map{
+		keys:        make([]string, 0, len(values)),
+		values:      make(map[string]string, len(values)),
+		provenances: make(map[string]Provenance, len(values)),
+	}
+
+	for key, val := range values {
+		m.keys = append(m.keys, key)
+		m.values[key] = val
+		m.provenances[key] = Provenance{Key: key, Source: source}
+	}
+
+	sort.Strings(m.keys)
+
+	return m
+}
+
+// Snapshot captures the values of the package [Source], the process environment unless
+// replaced with [SetSource], into a [Map]. Resolving variables through the snapshot with the
+// *From getters gives consistent reads that are not affected by later changes to the
+// environment.
+func Snapshot() *Map {
+	return SnapshotOf(currentSource())
+}
+
+// SnapshotOf captures the values of src into a [Map]. The provenance of the values in src is
+// preserved.
+func SnapshotOf(src Source) *Map {
+	keys := src.Keys()
+
+	m := &Map{
+		keys:        make([]string, 0, len(keys)),
+		values:      make(map[string]string, len(keys)),
+		provenances: make(map[string]Provenance, len(keys)),
+	}
+
+	for _, key := range keys {
+		val, ok := src.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		if _, ok := m.values[key]; !ok {
+			m.keys = append(m.keys, key)
+		}
+
+		m.values[key] = val
+		m.provenances[key] = provenanceOf(src, key)
+	}
+
+	sort.Strings(m.keys)
+
+	return m
+}
+
+// parseFromMap resolves the variable named by the key through m without the bookkeeping of
+// [lookupValue]. It reports false when that bookkeeping is needed, because reads are tracked
+// or logged, aliases are registered, or the value is empty or could not be parsed.
+func parseFromMap[V any](m *Map, key string, defaultValue V, parse func(string) (V, error)) (V, bool) {
+	if observingReads() || aliasesInUse.Load() {
+		return defaultValue, false
+	}
+
+	val, ok := m.values[key]
+	if !ok {
+		return defaultValue, true
+	}
+
+	if val == "" {
+		return defaultValue, false
+	}
+
+	parsed, err := parse(val)
+	if err != nil {
+		return defaultValue, false
+	}
+
+	return parsed, true
+}
+
+// Lookup retrieves the value of the variable named by the key.
+func (m *Map) Lookup(key string) (string, bool) {
+	val, ok := m.values[key]
+
+	return val, ok
+}
+
+// Keys returns the keys of all variables in lexicographical order.
+func (m *Map) Keys() []string {
+	keys := make([]string, len(m.keys))
+	copy(keys, m.keys)
+
+	return keys
+}
+
+// Provenance returns the provenance of the variable named by the key.
+func (m *Map) Provenance(key string) Provenance {
+	return m.provenances[key]
+}
@@ -0,0 +1,70 @@
+package env
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Setenv("KEY_SNAPSHOT_TIMEOUT", "2s")
+
+	s := Snapshot()
+
+	t.Setenv("KEY_SNAPSHOT_TIMEOUT", "3s")
+
+	if val := GetDurationFrom(s, "KEY_SNAPSHOT_TIMEOUT", time.Second); val != 2*time.Second {
+		t.Errorf("expected snapshot value %s got %s", 2*time.Second, val)
+	}
+
+	if val := GetDuration("KEY_SNAPSHOT_TIMEOUT", time.Second); val != 3*time.Second {
+		t.Errorf("expected env var value %s got %s", 3*time.Second, val)
+	}
+
+	if p := s.Provenance("KEY_SNAPSHOT_TIMEOUT"); p.Source != ProvenanceOS {
+		t.Errorf("expected snapshot to preserve provenance got %s", p)
+	}
+}
+
+func TestNewMap(t *testing.T) {
+	values := map[string]string{"KEY_MAP_B": "1,2", "KEY_MAP_A": "x"}
+	m := NewMap(values)
+
+	values["KEY_MAP_A"] = "y"
+
+	if val := GetStringFrom(m, "KEY_MAP_A", ""); val != "x" {
+		t.Errorf("expected map to copy values got %q", val)
+	}
+
+	if err := equalSlices(GetIntSliceFrom(m, "KEY_MAP_B", ",", 10, []int{}), []int{1, 2}); err != nil {
+		t.Errorf("expected map value %s", err.Error())
+	}
+
+	if err := equalSlices(m.Keys(), []string{"KEY_MAP_A", "KEY_MAP_B"}); err != nil {
+		t.Errorf("expected sorted keys %s", err.Error())
+	}
+
+	if p := m.Provenance("KEY_MAP_A"); p.Source != ProvenanceMap {
+		t.Errorf("unexpected provenance %s", p)
+	}
+}
+
+func TestMapFeatures(t *testing.T) {
+	m := NewMap(map[string]string{"KEY_MAP_OLD": "old", "KEY_MAP_EMPTY": "", "KEY_MAP_INVALID": "x"})
+
+	RegisterAliases("KEY_MAP_NEW", Alias{Key: "KEY_MAP_OLD"})
+	t.Cleanup(func() { RegisterAliases("KEY_MAP_NEW") })
+
+	if val := GetStringFrom(m, "KEY_MAP_NEW", ""); val != "old" {
+		t.Errorf("expected alias value %q got %q", "old", val)
+	}
+
+	setEmptyPolicy(t, EmptyAsUnset)
+
+	if val := GetStringFrom(m, "KEY_MAP_EMPTY", "default"); val != "default" {
+		t.Errorf("expected default value %q got %q", "default", val)
+	}
+
+	if val := GetIntFrom(m, "KEY_MAP_INVALID", 10, 7); val != 7 {
+		t.Errorf("expected default value %d got %d", 7, val)
+	}
+}
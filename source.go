@@ -0,0 +1,166 @@
+package env
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Source provides the raw values of environment variables. The getters resolve values
+// through the package Source, which is the process environment unless replaced with
+// [SetSource].
+type Source interface {
+	// Lookup retrieves the value of the variable named by the key. The boolean reports
+	// whether the variable is present.
+	Lookup(key string) (string, bool)
+	// Keys returns the keys of all variables present in the source.
+	Keys() []string
+}
+
+// OS returns a [Source] reading the environment of the current process.
+func OS() Source {
+	return osSource{}
+}
+
+type osSource struct{}
+
+func (osSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (osSource) Keys() []string {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+
+	for _, kv := range environ {
+		key, _, _ := strings.Cut(kv, "=")
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (osSource) Provenance(key string) Provenance {
+	return Provenance{Key: key, Source: ProvenanceOS}
+}
+
+// getFromOS resolves the variable named by the key through the process environment. Unless
+// the bookkeeping of [lookupValue] is needed, see [parseFromMap], the value is read straight
+// from the environment.
+func getFromOS[V any](key string, defaultValue V, parse func(string) (V, error)) V {
+	if observingReads() || aliasesInUse.Load() {
+		return getFrom(osSource{}, key, defaultValue, parse)
+	}
+
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	if val != "" {
+		if parsed, err := parse(val); err == nil {
+			return parsed
+		}
+	}
+
+	return getFrom(osSource{}, key, defaultValue, parse)
+}
+
+type sourceHolder struct {
+	src Source
+}
+
+var packageSource atomic.Pointer[sourceHolder]
+
+// SetSource replaces the [Source] the getters resolve values through. Passing nil restores
+// the process environment.
+func SetSource(src Source) {
+	if src == nil {
+		packageSource.Store(nil)
+		return
+	}
+
+	packageSource.Store(&sourceHolder{src: src})
+}
+
+// CurrentSource returns the [Source] the getters resolve values through, the process
+// environment unless replaced with [SetSource].
+func CurrentSource() Source {
+	return currentSource()
+}
+
+func currentSource() Source {
+	if holder := packageSource.Load(); holder != nil {
+		return holder.src
+	}
+
+	return osSource{}
+}
+
+// FileSecrets returns a [Source] that resolves a key missing from src by reading the file
+// named by the value of the key suffixed with _FILE, following the convention used for
+// Docker and Kubernetes secrets. A single trailing newline is removed from the contents of
+// the file. A key whose file could not be read is reported as not present.
+func FileSecrets(src Source) Source {
+	return fileSecrets{src: src}
+}
+
+type fileSecrets struct {
+	src Source
+}
+
+const fileSuffix = "_FILE"
+
+func (s fileSecrets) Lookup(key string) (string, bool) {
+	if val, ok := s.src.Lookup(key); ok {
+		return val, true
+	}
+
+	path, ok := s.src.Lookup(key + fileSuffix)
+	if !ok {
+		return "", false
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	val := strings.TrimSuffix(string(contents), "\n")
+	val = strings.TrimSuffix(val, "\r")
+
+	return val, true
+}
+
+func (s fileSecrets) Keys() []string {
+	keys := s.src.Keys()
+
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		seen[key] = struct{}{}
+	}
+
+	for _, key := range keys {
+		trimmed, ok := strings.CutSuffix(key, fileSuffix)
+		if !ok || trimmed == "" {
+			continue
+		}
+
+		if _, ok := seen[trimmed]; !ok {
+			seen[trimmed] = struct{}{}
+			keys = append(keys, trimmed)
+		}
+	}
+
+	return keys
+}
+
+func (s fileSecrets) Provenance(key string) Provenance {
+	if _, ok := s.src.Lookup(key); ok {
+		return provenanceOf(s.src, key)
+	}
+
+	path, _ := s.src.Lookup(key + fileSuffix)
+
+	return Provenance{Key: key, Source: ProvenanceFile, File: path}
+}
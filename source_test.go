@@ -0,0 +1,64 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetSource(t *testing.T) {
+	d, err := ParseDotenv(strings.NewReader("KEY_SOURCE_PORT=9090"), "test.env")
+	if err != nil {
+		t.Fatalf("parse dotenv failed %s", err.Error())
+	}
+
+	SetSource(d)
+	t.Cleanup(func() { SetSource(nil) })
+
+	if val := GetInt("KEY_SOURCE_PORT", 10, 0); val != 9090 {
+		t.Errorf("expected dotenv value %d got %d", 9090, val)
+	}
+
+	SetSource(nil)
+
+	if val := GetInt("KEY_SOURCE_PORT", 10, 0); val != 0 {
+		t.Errorf("expected default value %d got %d", 0, val)
+	}
+}
+
+func TestFileSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatalf("write secret failed %s", err.Error())
+	}
+
+	t.Setenv("KEY_SECRET_PASSWORD_FILE", path)
+	t.Setenv("KEY_SECRET_MISSING_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	src := FileSecrets(OS())
+
+	val, ok := src.Lookup("KEY_SECRET_PASSWORD")
+	if !ok || val != "hunter2" {
+		t.Errorf("expected secret value %q got %q", "hunter2", val)
+	}
+
+	if _, ok := src.Lookup("KEY_SECRET_MISSING"); ok {
+		t.Errorf("expected unreadable secret to be reported as not present")
+	}
+
+	p := provenanceOf(src, "KEY_SECRET_PASSWORD")
+	if p.Source != ProvenanceFile || p.File != path {
+		t.Errorf("unexpected provenance %s", p)
+	}
+
+	t.Setenv("KEY_SECRET_PASSWORD", "override")
+
+	if val, _ := src.Lookup("KEY_SECRET_PASSWORD"); val != "override" {
+		t.Errorf("expected variable to take precedence over file got %q", val)
+	}
+
+	if p := provenanceOf(src, "KEY_SECRET_PASSWORD"); p.Source != ProvenanceOS {
+		t.Errorf("unexpected provenance %s", p)
+	}
+}
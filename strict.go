@@ -0,0 +1,161 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// readKeys holds every key looked up by a getter.
+var readKeys sync.Map
+
+func recordRead(key string) {
+	if !tracking.Load() {
+		return
+	}
+
+	if _, ok := readKeys.Load(key); !ok {
+		readKeys.Store(key, struct{}{})
+	}
+}
+
+// UnknownKey is an environment variable that was neither read by a getter nor declared
+// with one of the *Var functions.
+type UnknownKey struct {
+	// Key is the name of the unknown environment variable.
+	Key string
+	// Suggestion is the known key closest to Key, or empty if no known key is close enough
+	// to be a likely misspelling.
+	Suggestion string
+}
+
+// UnknownKeyError is returned by [CheckUnknown] for every unknown environment variable.
+type UnknownKeyError struct {
+	UnknownKey
+}
+
+func (e *UnknownKeyError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("env: unknown variable %s", e.Key)
+	}
+
+	return fmt.Sprintf("env: unknown variable %s, did you mean %s?", e.Key, e.Suggestion)
+}
+
+// UnknownKeys returns every variable present in the package [Source] whose key starts with the
+// prefix and that was neither read by a getter nor declared, sorted by key. Each unknown key
+// carries the closest known key with the same prefix as a suggestion when the two are within
+// a small edit distance of each other.
+//
+// Variables are only known once they were read or declared, so UnknownKeys is meant to be
+// called after the configuration has been loaded with the reads tracked. It panics with
+// [ErrTrackingDisabled] if tracking is not enabled, see [SetTracking].
+func UnknownKeys(prefix string) []UnknownKey {
+	if !tracking.Load() {
+		panic(ErrTrackingDisabled)
+	}
+
+	known := make(map[string]struct{})
+
+	readKeys.Range(func(key, _ any) bool {
+		known[key.(string)] = struct{}{}
+		return true
+	})
+
+	VisitAll(func(v *Variable) {
+		known[v.Key] = struct{}{}
+	})
+
+	candidates := make([]string, 0, len(known))
+	for key := range known {
+		if strings.HasPrefix(key, prefix) {
+			candidates = append(candidates, key)
+		}
+	}
+
+	sort.Strings(candidates)
+
+	var unknown []UnknownKey
+
+	for _, key := range currentSource().Keys() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		if _, ok := known[key]; ok {
+			continue
+		}
+
+		unknown = append(unknown, UnknownKey{Key: key, Suggestion: suggest(key, candidates)})
+	}
+
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].Key < unknown[j].Key
+	})
+
+	return unknown
+}
+
+// CheckUnknown returns an error joining an [*UnknownKeyError] for every key reported by
+// [UnknownKeys], or nil if there are none. It returns [ErrTrackingDisabled] if tracking is
+// not enabled, see [SetTracking].
+func CheckUnknown(prefix string) error {
+	if !tracking.Load() {
+		return ErrTrackingDisabled
+	}
+
+	var errs []error
+
+	for _, unknown := range UnknownKeys(prefix) {
+		errs = append(errs, &UnknownKeyError{UnknownKey: unknown})
+	}
+
+	return errors.Join(errs...)
+}
+
+// suggest returns the candidate closest to key, provided the edit distance between them is
+// small relative to the length of the key.
+func suggest(key string, candidates []string) string {
+	maxDistance := max(2, len(key)/5)
+
+	suggestion := ""
+	best := maxDistance + 1
+
+	for _, candidate := range candidates {
+		if d := editDistance(key, candidate); d < best {
+			suggestion = candidate
+			best = d
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,68 @@
+package env
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	isolateRegistry(t)
+	StringVar("STRICT_DATABASE_URL", "", "")
+	GetInt("STRICT_PORT", 10, 0)
+
+	t.Setenv("STRICT_DATABSE_URL", "postgres://")
+	t.Setenv("STRICT_PORT", "80")
+	t.Setenv("STRICT_COMPLETELY_DIFFERENT", "1")
+	t.Setenv("OTHER_DATABSE_URL", "postgres://")
+
+	unknown := UnknownKeys("STRICT_")
+
+	expected := []UnknownKey{
+		{Key: "STRICT_COMPLETELY_DIFFERENT"},
+		{Key: "STRICT_DATABSE_URL", Suggestion: "STRICT_DATABASE_URL"},
+	}
+
+	if err := equalSlices(unknown, expected); err != nil {
+		t.Errorf("expected unknown keys %v got %v: %s", expected, unknown, err.Error())
+	}
+}
+
+func TestCheckUnknown(t *testing.T) {
+	GetString("CHECK_UNKNOWN_LEVEL", "")
+
+	if err := CheckUnknown("CHECK_UNKNOWN_"); err != nil {
+		t.Errorf("expected no error got %s", err.Error())
+	}
+
+	t.Setenv("CHECK_UNKNOWN_LEVLE", "debug")
+
+	err := CheckUnknown("CHECK_UNKNOWN_")
+
+	var unknownErr *UnknownKeyError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected unknown key error got %v", err)
+	}
+
+	expected := "env: unknown variable CHECK_UNKNOWN_LEVLE, did you mean CHECK_UNKNOWN_LEVEL?"
+	if unknownErr.Error() != expected {
+		t.Errorf("expected error %q got %q", expected, unknownErr.Error())
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"DATABSE", "DATABASE", 1},
+	}
+
+	for _, test := range tests {
+		if d := editDistance(test.a, test.b); d != test.distance {
+			t.Errorf("expected distance between %q and %q to be %d got %d", test.a, test.b, test.distance, d)
+		}
+	}
+}
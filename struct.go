@@ -0,0 +1,621 @@
+package env
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structField is a field of a struct bound to an environment variable, see [Unmarshal] for
+// the tags controlling the binding.
+type structField struct {
+	index        []int
+	typ          reflect.Type
+	key          string
+	separator    string
+	slice        sliceOptions
+	mapSeparator string
+	optional     bool
+	base         int
+	defaultValue string
+	hasDefault   bool
+	required     bool
+	secret       bool
+	usage        string
+	min          *reflect.Value
+	max          *reflect.Value
+	enum         []reflect.Value
+	// elems holds the fields of the elements of a slice of structs, whose keys are prefixed
+	// by the key of the slice followed by the index of the element and an underscore, or of
+	// the entries of a map of structs, whose keys are prefixed by the key of the map followed
+	// by the key of the entry and the map separator.
+	elems []structField
+}
+
+func structFields(t reflect.Type, prefix string) ([]structField, error) {
+	var fields []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, tagged := f.Tag.Lookup("env")
+		if tag == "-" {
+			continue
+		}
+
+		if !tagged {
+			if isStructSlice(f.Type) {
+				if f.Tag.Get("envPrefix") == "" {
+					return nil, fmt.Errorf("env: field %s is a slice of structs without an envPrefix tag", f.Name)
+				}
+
+				elems, err := structFields(f.Type.Elem(), "")
+				if err != nil {
+					return nil, err
+				}
+
+				start, err := indexStart(f)
+				if err != nil {
+					return nil, err
+				}
+
+				fields = append(fields, structField{
+					index: []int{i},
+					typ:   f.Type,
+					key:   prefix + f.Tag.Get("envPrefix"),
+					slice: sliceOptions{indexed: &indexedKeys{start: start}},
+					elems: elems,
+				})
+
+				continue
+			}
+
+			if isStructMap(f.Type) {
+				elems, err := structFields(f.Type.Elem(), "")
+				if err != nil {
+					return nil, err
+				}
+
+				separator, err := mapSeparator(f)
+				if err != nil {
+					return nil, err
+				}
+
+				fields = append(fields, structField{
+					index:        []int{i},
+					typ:          f.Type,
+					key:          prefix + f.Tag.Get("envPrefix"),
+					mapSeparator: separator,
+					elems:        elems,
+				})
+
+				continue
+			}
+
+			if f.Type.Kind() != reflect.Struct || f.Type == urlType {
+				continue
+			}
+
+			nested, err := structFields(f.Type, prefix+f.Tag.Get("envPrefix"))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, n := range nested {
+				n.index = append([]int{i}, n.index...)
+				fields = append(fields, n)
+			}
+
+			continue
+		}
+
+		key, opts, _ := strings.Cut(tag, ",")
+		if key == "" {
+			return nil, fmt.Errorf("env: field %s has an empty key", f.Name)
+		}
+
+		field := structField{
+			index:     []int{i},
+			typ:       f.Type,
+			key:       prefix + key,
+			separator: ",",
+			base:      10,
+		}
+
+		// An Optional field is bound like a field of the type of its value.
+		if isOptional(f.Type) {
+			field.typ = reflect.New(f.Type).Interface().(optionalField).valueType()
+			field.optional = true
+		}
+
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "required":
+				field.required = true
+			case "secret":
+				field.secret = true
+			default:
+				return nil, fmt.Errorf("env: field %s has unknown option %q", f.Name, opt)
+			}
+		}
+
+		if separator, ok := f.Tag.Lookup("envSeparator"); ok {
+			field.separator = separator
+		}
+
+		if names, ok := f.Tag.Lookup("envSlice"); ok {
+			slice, err := parseSliceOptions(strings.Split(names, ","))
+			if err != nil {
+				return nil, fmt.Errorf("env: field %s has %w", f.Name, err)
+			}
+
+			field.slice = slice
+		}
+
+		if separator, ok := f.Tag.Lookup("envIndexed"); ok {
+			if field.typ.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("env: field %s is indexed but is not a slice", f.Name)
+			}
+
+			start, err := indexStart(f)
+			if err != nil {
+				return nil, err
+			}
+
+			field.slice.indexed = &indexedKeys{separator: separator, start: start}
+		}
+
+		if field.typ.Kind() == reflect.Map {
+			separator, err := mapSeparator(f)
+			if err != nil {
+				return nil, err
+			}
+
+			field.mapSeparator = separator
+		}
+
+		if base, ok := f.Tag.Lookup("envBase"); ok {
+			parsedBase, err := strconv.Atoi(base)
+			if err != nil || !validBase(parsedBase) {
+				return nil, fmt.Errorf("env: field %s has invalid base %q", f.Name, base)
+			}
+
+			field.base = parsedBase
+		}
+
+		field.defaultValue, field.hasDefault = f.Tag.Lookup("envDefault")
+		switch {
+		case field.hasDefault && field.typ.Kind() == reflect.Map:
+			return nil, fmt.Errorf("env: field %s is a map and can not have a default", f.Name)
+		case field.hasDefault && field.optional:
+			return nil, fmt.Errorf("env: field %s is optional and can not have a default", f.Name)
+		}
+
+		field.usage = f.Tag.Get("envUsage")
+
+		if !supportedType(field.typ) || (field.optional && field.typ.Kind() == reflect.Map) {
+			return nil, fmt.Errorf("env: field %s has unsupported type %s", f.Name, f.Type)
+		}
+
+		if err := field.parseConstraints(f.Tag); err != nil {
+			return nil, fmt.Errorf("env: field %s has %w", f.Name, err)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// indexStart returns the index of the first element of an indexed slice field, set by its
+// envIndexStart tag.
+func indexStart(f reflect.StructField) (int, error) {
+	start, ok := f.Tag.Lookup("envIndexStart")
+	if !ok {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(start)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("env: field %s has invalid index start %q", f.Name, start)
+	}
+
+	return parsed, nil
+}
+
+// isStructSlice reports whether t is a slice of structs other than URLs, whose elements are
+// bound with indexed prefixes.
+func isStructSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct && t.Elem() != urlType
+}
+
+// isStructMap reports whether t is a map of structs other than URLs keyed by strings, whose
+// entries are bound with prefixes holding their keys.
+func isStructMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		t.Elem().Kind() == reflect.Struct && t.Elem() != urlType
+}
+
+// mapSeparator returns the separator of the keys of the entries of a map field, set by its
+// envMapSeparator tag.
+func mapSeparator(f reflect.StructField) (string, error) {
+	separator, ok := f.Tag.Lookup("envMapSeparator")
+	if !ok {
+		return "_", nil
+	}
+
+	if separator == "" {
+		return "", fmt.Errorf("env: field %s has an empty map separator", f.Name)
+	}
+
+	return separator, nil
+}
+
+// entryKey returns the key of the variable of the entry named by name of a map field.
+func (f structField) entryKey(name string) string {
+	return f.key + f.mapSeparator + name
+}
+
+// entryPrefix returns the prefix of the keys of the entry named by name of a map of structs.
+func (f structField) entryPrefix(name string) string {
+	return f.key + name + f.mapSeparator
+}
+
+// entry returns the field the entries of a map field are bound to, which is the field with
+// the type of the values of the map.
+func (f structField) entry() structField {
+	f.typ = f.typ.Elem()
+
+	return f
+}
+
+// elemPrefix returns the prefix of the keys of the element at index i of a slice of structs.
+func (f structField) elemPrefix(i int) string {
+	return f.key + strconv.Itoa(i) + "_"
+}
+
+// prefixFields returns a copy of the fields with their keys prefixed.
+func prefixFields(fields []structField, prefix string) []structField {
+	prefixed := make([]structField, 0, len(fields))
+
+	for _, f := range fields {
+		f.key = prefix + f.key
+		prefixed = append(prefixed, f)
+	}
+
+	return prefixed
+}
+
+func supportedType(t reflect.Type) bool {
+	if t.Kind() == reflect.Map {
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() != reflect.Map && supportedType(t.Elem())
+	}
+
+	if t.Kind() == reflect.Slice {
+		return supportedScalarType(t.Elem())
+	}
+
+	return supportedScalarType(t)
+}
+
+func supportedScalarType(t reflect.Type) bool {
+	if t == durationType || t == urlType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// elem returns the type of the value of the field, or of its elements if it is a slice, or of
+// the values or elements of the values of its entries if it is a map.
+func (f structField) elem() reflect.Type {
+	if f.typ.Kind() == reflect.Map {
+		return f.entry().elem()
+	}
+
+	if f.typ.Kind() == reflect.Slice {
+		return f.typ.Elem()
+	}
+
+	return f.typ
+}
+
+// parseConstraints parses the envMin, envMax and envEnum tags restricting the values of the
+// field, or of its elements if it is a slice.
+func (f *structField) parseConstraints(tag reflect.StructTag) error {
+	for _, bound := range []struct {
+		name string
+		dst  **reflect.Value
+	}{{"envMin", &f.min}, {"envMax", &f.max}} {
+		s, ok := tag.Lookup(bound.name)
+		if !ok {
+			continue
+		}
+
+		if !orderedType(f.elem()) {
+			return fmt.Errorf("%s tag but type %s is not ordered", bound.name, f.typ)
+		}
+
+		v, err := parseScalar(f.elem(), s, f.base)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", bound.name, s, err)
+		}
+
+		*bound.dst = &v
+	}
+
+	if s, ok := tag.Lookup("envEnum"); ok {
+		for _, val := range strings.Split(s, ",") {
+			v, err := parseScalar(f.elem(), val, f.base)
+			if err != nil {
+				return fmt.Errorf("invalid envEnum value %q: %w", val, err)
+			}
+
+			f.enum = append(f.enum, v)
+		}
+	}
+
+	return nil
+}
+
+func orderedType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseValue parses s into a new value of the type of the field with the same rules as the
+// getter for the type, and checks it against the constraints of the field. The elements of a
+// slice are parsed like [sliceParser] parses them.
+func (f structField) parseValue(s string) (reflect.Value, error) {
+	if f.typ.Kind() != reflect.Slice {
+		v, err := parseScalar(f.typ, s, f.base)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return v, f.check(v)
+	}
+
+	parts, err := splitSlice(s, f.separator, f.slice)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return f.parseElements(parts)
+}
+
+// parseElements parses the elements of a slice field into a new slice like
+// [structField.parseValue] parses them.
+func (f structField) parseElements(parts []string) (reflect.Value, error) {
+	slice := reflect.MakeSlice(f.typ, 0, len(parts))
+
+	var errs []error
+
+	for i, part := range parts {
+		elem, err := parseScalar(f.typ.Elem(), part, f.base)
+		if err == nil {
+			err = f.check(elem)
+		}
+
+		if err != nil {
+			errs = append(errs, &ElementError{Index: f.slice.elementIndex(i), Value: part, Err: err})
+			continue
+		}
+
+		slice = reflect.Append(slice, elem)
+	}
+
+	if err := f.slice.elementsError(errs); err != nil {
+		return slice, err
+	}
+
+	return slice, nil
+}
+
+// check returns an error if the scalar v is out of the range or not one of the allowed values
+// of the field.
+func (f structField) check(v reflect.Value) error {
+	if f.min != nil && compareValues(v, *f.min) < 0 {
+		return fmt.Errorf("%s is less than the minimum %s", formatScalar(v, f.base), formatScalar(*f.min, f.base))
+	}
+
+	if f.max != nil && compareValues(v, *f.max) > 0 {
+		return fmt.Errorf("%s is greater than the maximum %s", formatScalar(v, f.base), formatScalar(*f.max, f.base))
+	}
+
+	if len(f.enum) == 0 {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(f.enum))
+	for _, e := range f.enum {
+		if reflect.DeepEqual(v.Interface(), e.Interface()) {
+			return nil
+		}
+
+		allowed = append(allowed, strconv.Quote(formatScalar(e, f.base)))
+	}
+
+	return fmt.Errorf("%q is not one of %s", formatScalar(v, f.base), strings.Join(allowed, ", "))
+}
+
+func compareValues(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	default:
+		return cmp.Compare(a.Float(), b.Float())
+	}
+}
+
+func parseScalar(t reflect.Type, s string, base int) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+
+	switch {
+	case t == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v.SetInt(int64(d))
+	case t == urlType:
+		u, err := parseURL(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v.Set(reflect.ValueOf(u))
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			v.SetString(s)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			v.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(s, base, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			v.SetInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(s, base, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			v.SetUint(u)
+		case reflect.Float32, reflect.Float64:
+			fl, err := strconv.ParseFloat(s, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			v.SetFloat(fl)
+		}
+	}
+
+	return v, nil
+}
+
+// formatValue formats v the way it would be written in the environment, so that parsing the
+// result with the getter for the type returns v. An error is returned for a slice whose
+// elements would not be split back unchanged, such as elements containing the separator of
+// a slice that is neither quoted nor escaped.
+func (f structField) formatValue(v reflect.Value) (string, error) {
+	if f.typ.Kind() != reflect.Slice {
+		return formatScalar(v, f.base), nil
+	}
+
+	parts := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		parts = append(parts, formatScalar(v.Index(i), f.base))
+	}
+
+	if !f.slice.quoted && !f.slice.escaped && f.separator != "" {
+		for _, part := range parts {
+			if strings.Contains(part, f.separator) {
+				return "", fmt.Errorf("env: element %q of %s contains the separator %q, the field must be quoted or escaped", part, f.key, f.separator)
+			}
+		}
+	}
+
+	joined := joinSlice(parts, f.separator, f.slice)
+
+	if split, err := splitSlice(joined, f.separator, f.slice); err != nil || !slices.Equal(split, parts) {
+		return "", fmt.Errorf("env: elements of %s can not be written with the separator %q", f.key, f.separator)
+	}
+
+	return joined, nil
+}
+
+func formatScalar(v reflect.Value, base int) string {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Type() == urlType:
+		return formatURL(v.Interface().(url.URL))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), formatBase(base))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), formatBase(base))
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	default:
+		return ""
+	}
+}
+
+// validBase reports whether base is accepted by [strconv.ParseInt] and, once mapped by
+// [formatBase], by [strconv.FormatInt]: 0 or between 2 and 36.
+func validBase(base int) bool {
+	return base == 0 || (base >= 2 && base <= 36)
+}
+
+// formatBase returns the base integers parsed in base are formatted in. Base 0 infers the
+// base from the prefix of the value, so those integers are formatted in base 10.
+func formatBase(base int) int {
+	if base == 0 {
+		return 10
+	}
+
+	return base
+}
+
+// structValue returns the struct value cfg points to, or cfg itself if addressable is false
+// and cfg is a struct.
+func structValue(cfg any, addressable bool) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg)
+
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		return v.Elem(), nil
+	}
+
+	if !addressable && v.Kind() == reflect.Struct {
+		return v, nil
+	}
+
+	if addressable {
+		return reflect.Value{}, fmt.Errorf("env: expected a non-nil pointer to a struct got %T", cfg)
+	}
+
+	return reflect.Value{}, fmt.Errorf("env: expected a struct or a pointer to a struct got %T", cfg)
+}
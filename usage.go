@@ -0,0 +1,178 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const redacted = "******"
+
+// usageEntry is the rendered form of a declared [Variable] shared by all usage renderers.
+type usageEntry struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Default     string `json:"default"`
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+	Description string `json:"description"`
+	Value       string `json:"value,omitempty"`
+	Set         bool   `json:"set"`
+}
+
+func usageEntries() []usageEntry {
+	var entries []usageEntry
+
+	VisitAll(func(v *Variable) {
+		entry := usageEntry{
+			Key:         v.Key,
+			Type:        v.Type,
+			Default:     v.Default,
+			Required:    v.Required,
+			Secret:      v.Secret,
+			Description: v.Usage,
+		}
+
+		entry.Value, entry.Set = currentSource().Lookup(v.Key)
+		if entry.Set && v.Secret {
+			entry.Value = redacted
+		}
+
+		entries = append(entries, entry)
+	})
+
+	return entries
+}
+
+// PrintUsage writes a description of every declared variable to w in the format used by
+// [flag.PrintDefaults]. The current value of a variable is included when it is present in
+// the environment, redacted if the variable was marked as secret.
+func PrintUsage(w io.Writer) error {
+	var b strings.Builder
+
+	for _, entry := range usageEntries() {
+		fmt.Fprintf(&b, "  %s %s", entry.Key, entry.Type)
+		if entry.Required {
+			b.WriteString(" (required)")
+		}
+
+		b.WriteString("\n    \t")
+		b.WriteString(strings.ReplaceAll(entry.Description, "\n", "\n    \t"))
+
+		if entry.Default != "" {
+			fmt.Fprintf(&b, " (default %s)", strconv.Quote(entry.Default))
+		}
+
+		if entry.Set {
+			fmt.Fprintf(&b, "\n    \tcurrent value %s", strconv.Quote(entry.Value))
+		}
+
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteMarkdown writes a Markdown table describing every declared variable to w.
+func WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("| Key | Type | Default | Required | Description | Current value |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, entry := range usageEntries() {
+		required := "no"
+		if entry.Required {
+			required = "yes"
+		}
+
+		value := ""
+		if entry.Set {
+			value = markdownCode(entry.Value)
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			entry.Key,
+			markdownCell(entry.Type),
+			markdownCode(entry.Default),
+			required,
+			markdownCell(entry.Description),
+			value,
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + markdownCell(s) + "`"
+}
+
+// WriteJSON writes a JSON array describing every declared variable to w.
+func WriteJSON(w io.Writer) error {
+	entries := usageEntries()
+	if entries == nil {
+		entries = []usageEntry{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
+}
+
+// WriteDotenvExample writes a .env.example file listing every declared variable with its
+// default value to w. Descriptions, types and whether a variable is required are written
+// as comments above each assignment. Current values are never written.
+func WriteDotenvExample(w io.Writer) error {
+	var b strings.Builder
+
+	for i, entry := range usageEntries() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		for _, line := range strings.Split(entry.Description, "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "# %s\n", line)
+			}
+		}
+
+		fmt.Fprintf(&b, "# type: %s", entry.Type)
+		if entry.Required {
+			b.WriteString(", required")
+		}
+
+		fmt.Fprintf(&b, "\n%s=%s\n", entry.Key, dotenvQuote(entry.Default))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// dotenvQuote quotes s when it can not be written verbatim on the right hand side of a
+// dotenv assignment.
+func dotenvQuote(s string) string {
+	if s == "" || !strings.ContainsAny(s, " \t\n\"'#\\$") {
+		return s
+	}
+
+	return strconv.Quote(s)
+}
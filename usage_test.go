@@ -0,0 +1,106 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintUsage(t *testing.T) {
+	isolateRegistry(t)
+
+	DurationVar("KEY_USAGE_TIMEOUT", 5*time.Second, "HTTP client timeout").MarkRequired()
+	StringVar("KEY_USAGE_PASSWORD", "", "database password").MarkSecret()
+
+	t.Setenv("KEY_USAGE_PASSWORD", "hunter2")
+
+	var b bytes.Buffer
+	if err := PrintUsage(&b); err != nil {
+		t.Errorf("print usage failed %s", err.Error())
+	}
+
+	out := b.String()
+
+	expected := "  KEY_USAGE_TIMEOUT duration (required)\n    \tHTTP client timeout (default \"5s\")\n"
+	if !strings.Contains(out, expected) {
+		t.Errorf("expected usage to contain %q got %q", expected, out)
+	}
+
+	expected = "  KEY_USAGE_PASSWORD string\n    \tdatabase password\n    \tcurrent value \"******\"\n"
+	if !strings.Contains(out, expected) {
+		t.Errorf("expected usage to contain %q got %q", expected, out)
+	}
+
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("expected secret value to be redacted got %q", out)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	isolateRegistry(t)
+
+	IntVar("KEY_MARKDOWN_PORT", 10, 8080, "listen port | tcp")
+
+	var b bytes.Buffer
+	if err := WriteMarkdown(&b); err != nil {
+		t.Errorf("write markdown failed %s", err.Error())
+	}
+
+	expected := "| `KEY_MARKDOWN_PORT` | int | `8080` | no | listen port \\| tcp |  |\n"
+	if !strings.Contains(b.String(), expected) {
+		t.Errorf("expected markdown to contain %q got %q", expected, b.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	isolateRegistry(t)
+
+	BoolVar("KEY_JSON_DEBUG", false, "enable debug").MarkSecret()
+	t.Setenv("KEY_JSON_DEBUG", "true")
+
+	var b bytes.Buffer
+	if err := WriteJSON(&b); err != nil {
+		t.Errorf("write json failed %s", err.Error())
+	}
+
+	var entries []usageEntry
+	if err := json.Unmarshal(b.Bytes(), &entries); err != nil {
+		t.Errorf("unmarshal json failed %s", err.Error())
+	}
+
+	for _, entry := range entries {
+		if entry.Key != "KEY_JSON_DEBUG" {
+			continue
+		}
+
+		if entry.Type != "bool" || entry.Default != "false" || entry.Description != "enable debug" {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+
+		if !entry.Set || entry.Value != redacted {
+			t.Errorf("expected redacted current value got %q", entry.Value)
+		}
+
+		return
+	}
+
+	t.Errorf("expected KEY_JSON_DEBUG in %s", b.String())
+}
+
+func TestWriteDotenvExample(t *testing.T) {
+	isolateRegistry(t)
+
+	StringSliceVar("KEY_EXAMPLE_HOSTS", " ", []string{"a", "b"}, "upstream hosts").MarkRequired()
+
+	var b bytes.Buffer
+	if err := WriteDotenvExample(&b); err != nil {
+		t.Errorf("write dotenv example failed %s", err.Error())
+	}
+
+	expected := "# upstream hosts\n# type: []string, required\nKEY_EXAMPLE_HOSTS=\"a b\"\n"
+	if !strings.Contains(b.String(), expected) {
+		t.Errorf("expected dotenv example to contain %q got %q", expected, b.String())
+	}
+}
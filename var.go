@@ -0,0 +1,404 @@
+package env
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Variable describes an environment variable declared with one of the *Var functions.
+// The key, type, default value and usage are recorded once at declaration so they can be
+// inspected later, for example to document which variables a binary reads.
+type Variable struct {
+	// Key is the name of the environment variable.
+	Key string
+	// Type is a short name of the Go type the value is parsed into, e.g. "int" or "[]duration".
+	Type string
+	// Default is the default value formatted the way it would be written in the environment.
+	Default string
+	// Usage is a short description of the variable.
+	Usage string
+	// Required reports whether the variable was marked with [Var.MarkRequired].
+	Required bool
+	// Secret reports whether the variable was marked with [Var.MarkSecret]. The values of
+	// secret variables are redacted whenever they are rendered.
+	Secret bool
+}
+
+// Var is a typed handle to a declared environment variable. The value is looked up every
+// time [Var.Get] is called, so changes to the environment are observed.
+//
+// Vars are declared with the *Var functions, such as [DurationVar]. Unlike in the flag
+// package, where [flag.Duration] returns a pointer and [flag.DurationVar] binds one, they
+// return a Var and never take a pointer. They keep the Var suffix because the names without
+// it, such as String and Float, are the type constraints of the getters.
+type Var[T any] struct {
+	variable     *Variable
+	defaultValue T
+	parse        func(string) (T, error)
+	parseElems   func([]string) (T, error)
+	separator    string
+	slice        sliceOptions
+}
+
+// Get returns the current value of the variable, or its default value following the rules
+// of the getter matching the type the variable was declared with.
+func (v *Var[T]) Get() T {
+	src, parse := bindSlice(v.slice, currentSource(), v.separator, v.parse, v.parseElems)
+
+	return getFrom(src, v.variable.Key, v.defaultValue, parse)
+}
+
+// Lookup resolves the variable through src. The default value is returned if the variable is
+// not present. If the variable could not be parsed the default value is returned together
+// with a [*ParseError].
+func (v *Var[T]) Lookup(src Source) (T, error) {
+	src, parse := bindSlice(v.slice, src, v.separator, v.parse, v.parseElems)
+	val, _, err := lookupValue(src, v.variable.Key, v.defaultValue, parse)
+
+	return val, err
+}
+
+// Key returns the name of the environment variable.
+func (v *Var[T]) Key() string {
+	return v.variable.Key
+}
+
+// Variable returns the description of the variable recorded at declaration.
+func (v *Var[T]) Variable() *Variable {
+	return v.variable
+}
+
+// MarkRequired marks the variable as one that must be present in the environment and returns
+// the handle so it can be chained with the declaration.
+func (v *Var[T]) MarkRequired() *Var[T] {
+	registry.Lock()
+	defer registry.Unlock()
+
+	v.variable.Required = true
+
+	return v
+}
+
+// MarkSecret marks the variable as holding a secret value and returns the handle so it can be
+// chained with the declaration.
+func (v *Var[T]) MarkSecret() *Var[T] {
+	registry.Lock()
+	defer registry.Unlock()
+
+	v.variable.Secret = true
+
+	return v
+}
+
+var registry = struct {
+	sync.RWMutex
+	vars     map[string]*Variable
+	bindings map[string]binding
+}{vars: make(map[string]*Variable), bindings: make(map[string]binding)}
+
+// binding describes how the values of a declared variable are parsed, so they can be compared
+// by their typed form, see [DiffTyped], and described in a schema, see [RegistrySchema].
+type binding struct {
+	typ       reflect.Type
+	separator string
+	slice     sliceOptions
+	base      int
+	parse     func(string) (any, error)
+}
+
+// declare registers the variable. The separator, base and slice options are those of the parse
+// function, if it parses slices or integers.
+func declare[T any](variable *Variable, defaultValue T, parse func(string) (T, error), separator string, base int, opts ...SliceOption) *Var[T] {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.vars[variable.Key]; ok {
+		panic(fmt.Sprintf("env: variable redeclared: %s", variable.Key))
+	}
+
+	slice := newSliceOptions(opts)
+
+	registry.vars[variable.Key] = variable
+	registry.bindings[variable.Key] = binding{
+		typ:       reflect.TypeOf(&defaultValue).Elem(),
+		separator: separator,
+		slice:     slice,
+		base:      base,
+		parse: func(s string) (any, error) {
+			return parse(s)
+		},
+	}
+
+	return &Var[T]{variable: variable, defaultValue: defaultValue, parse: parse, separator: separator, slice: slice}
+}
+
+// declareSlice is like declare for a slice variable whose elements are parsed with parse.
+func declareSlice[V any](variable *Variable, defaultValue []V, parse func(string) (V, error), separator string, base int, opts ...SliceOption) *Var[[]V] {
+	v := declare(variable, defaultValue, sliceParser(separator, parse, opts...), separator, base, opts...)
+	v.parseElems = elementsParser(parse, v.slice)
+
+	return v
+}
+
+// LookupVariable returns the description of the declared variable named by the key, or nil
+// if none was declared.
+func LookupVariable(key string) *Variable {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.vars[key]
+}
+
+// variableParser returns the parse function of the declared variable named by the key, or
+// nil if none was declared.
+func variableParser(key string) func(string) (any, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if _, ok := registry.vars[key]; !ok {
+		return nil
+	}
+
+	return registry.bindings[key].parse
+}
+
+// VisitAll calls fn for every declared variable in lexicographical order of their keys.
+func VisitAll(fn func(*Variable)) {
+	registry.RLock()
+	vars := make([]*Variable, 0, len(registry.vars))
+	for _, variable := range registry.vars {
+		vars = append(vars, variable)
+	}
+	registry.RUnlock()
+
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Key < vars[j].Key
+	})
+
+	for _, variable := range vars {
+		fn(variable)
+	}
+}
+
+// StringVar declares a [String] variable with the specified key, default value and usage.
+// The returned handle resolves the value with [GetString].
+func StringVar[V String](key string, defaultValue V, usage string) *Var[V] {
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: string(defaultValue),
+		Usage:   usage,
+	}, defaultValue, parseString[V], "", 0)
+}
+
+// BoolVar declares a [Boolean] variable with the specified key, default value and usage.
+// The returned handle resolves the value with [GetBool].
+func BoolVar[V Boolean](key string, defaultValue V, usage string) *Var[V] {
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: strconv.FormatBool(bool(defaultValue)),
+		Usage:   usage,
+	}, defaultValue, parseBool[V], "", 0)
+}
+
+// IntVar declares a [Signed] variable with the specified key, base, default value and usage.
+// The returned handle resolves the value with [GetInt]. It panics if base is not 0 or between 2
+// and 36.
+func IntVar[V Signed](key string, base int, defaultValue V, usage string) *Var[V] {
+	checkBase(base, key)
+
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatInt(defaultValue, base),
+		Usage:   usage,
+	}, defaultValue, intParser[V](base), "", base)
+}
+
+// UintVar declares an [Unsigned] variable with the specified key, base, default value and usage.
+// The returned handle resolves the value with [GetUint]. It panics if base is not 0 or between 2
+// and 36.
+func UintVar[V Unsigned](key string, base int, defaultValue V, usage string) *Var[V] {
+	checkBase(base, key)
+
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatUint(defaultValue, base),
+		Usage:   usage,
+	}, defaultValue, uintParser[V](base), "", base)
+}
+
+// FloatVar declares a [Float] variable with the specified key, default value and usage.
+// The returned handle resolves the value with [GetFloat].
+func FloatVar[V Float](key string, defaultValue V, usage string) *Var[V] {
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatFloat(defaultValue),
+		Usage:   usage,
+	}, defaultValue, parseFloat[V], "", 0)
+}
+
+// DurationVar declares a [time.Duration] variable with the specified key, default value and usage.
+// The returned handle resolves the value with [GetDuration].
+func DurationVar(key string, defaultValue time.Duration, usage string) *Var[time.Duration] {
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: defaultValue.String(),
+		Usage:   usage,
+	}, defaultValue, time.ParseDuration, "", 0)
+}
+
+// URLVar declares a [net/url.URL] variable with the specified key, default value and usage.
+// The returned handle resolves the value with [GetURL].
+func URLVar(key string, defaultValue url.URL, usage string) *Var[url.URL] {
+	return declare(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: defaultValue.String(),
+		Usage:   usage,
+	}, defaultValue, parseURL, "", 0)
+}
+
+// StringSliceVar declares a [][String] variable with the specified key, separator, default value
+// and usage. The returned handle resolves the value with [GetStringSlice].
+func StringSliceVar[V String](key, separator string, defaultValue []V, usage string, opts ...SliceOption) *Var[[]V] {
+	return declareSlice(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatSlice(defaultValue, separator, func(v V) string { return string(v) }, opts...),
+		Usage:   usage,
+	}, defaultValue, parseString[V], separator, 0, opts...)
+}
+
+// BoolSliceVar declares a [][Boolean] variable with the specified key, separator, default value
+// and usage. The returned handle resolves the value with [GetBoolSlice].
+func BoolSliceVar[V Boolean](key, separator string, defaultValue []V, usage string, opts ...SliceOption) *Var[[]V] {
+	return declareSlice(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatSlice(defaultValue, separator, func(v V) string { return strconv.FormatBool(bool(v)) }, opts...),
+		Usage:   usage,
+	}, defaultValue, parseBool[V], separator, 0, opts...)
+}
+
+// IntSliceVar declares a [][Signed] variable with the specified key, separator, base, default value
+// and usage. The returned handle resolves the value with [GetIntSlice]. It panics if base is not 0
+// or between 2 and 36.
+func IntSliceVar[V Signed](key, separator string, base int, defaultValue []V, usage string, opts ...SliceOption) *Var[[]V] {
+	checkBase(base, key)
+
+	return declareSlice(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatSlice(defaultValue, separator, func(v V) string { return formatInt(v, base) }, opts...),
+		Usage:   usage,
+	}, defaultValue, intParser[V](base), separator, base, opts...)
+}
+
+// UintSliceVar declares a [][Unsigned] variable with the specified key, separator, base, default
+// value and usage. The returned handle resolves the value with [GetUintSlice]. It panics if base is
+// not 0 or between 2 and 36.
+func UintSliceVar[V Unsigned](key, separator string, base int, defaultValue []V, usage string, opts ...SliceOption) *Var[[]V] {
+	checkBase(base, key)
+
+	return declareSlice(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatSlice(defaultValue, separator, func(v V) string { return formatUint(v, base) }, opts...),
+		Usage:   usage,
+	}, defaultValue, uintParser[V](base), separator, base, opts...)
+}
+
+// DurationSliceVar declares a []time.Duration variable with the specified key, separator, default
+// value and usage. The returned handle resolves the value with [GetDurationSlice].
+func DurationSliceVar(key, separator string, defaultValue []time.Duration, usage string, opts ...SliceOption) *Var[[]time.Duration] {
+	return declareSlice(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatSlice(defaultValue, separator, time.Duration.String, opts...),
+		Usage:   usage,
+	}, defaultValue, time.ParseDuration, separator, 0, opts...)
+}
+
+// URLSliceVar declares a [][net/url.URL] variable with the specified key, separator, default value
+// and usage. The returned handle resolves the value with [GetURLSlice].
+func URLSliceVar(key, separator string, defaultValue []url.URL, usage string, opts ...SliceOption) *Var[[]url.URL] {
+	return declareSlice(&Variable{
+		Key:     key,
+		Type:    typeName(defaultValue),
+		Default: formatSlice(defaultValue, separator, formatURL, opts...),
+		Usage:   usage,
+	}, defaultValue, parseURL, separator, 0, opts...)
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// typeName returns the name used in a [Variable] for the type of v. Named types are reported
+// by their underlying kind so that a custom ~int type is documented as int.
+func typeName(v any) string {
+	return reflectTypeName(reflect.TypeOf(v))
+}
+
+func reflectTypeName(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return "duration"
+	case t == urlType:
+		return "url"
+	case t.Kind() == reflect.Slice:
+		return "[]" + reflectTypeName(t.Elem())
+	default:
+		return t.Kind().String()
+	}
+}
+
+// checkBase panics if integers can not be parsed and formatted in base, naming what they are
+// parsed for, see [validBase].
+func checkBase(base int, what string) {
+	if !validBase(base) {
+		panic(fmt.Sprintf("env: invalid base %d for %s", base, what))
+	}
+}
+
+func formatInt[V Signed](v V, base int) string {
+	return strconv.FormatInt(int64(v), formatBase(base))
+}
+
+func formatUint[V Unsigned](v V, base int) string {
+	return strconv.FormatUint(uint64(v), formatBase(base))
+}
+
+func formatFloat[V Float](v V) string {
+	bitSize := 64
+	if reflect.TypeOf(v).Kind() == reflect.Float32 {
+		bitSize = 32
+	}
+
+	return strconv.FormatFloat(float64(v), 'g', -1, bitSize)
+}
+
+func formatURL(u url.URL) string {
+	return u.String()
+}
+
+func formatSlice[V any](values []V, separator string, format func(V) string, opts ...SliceOption) string {
+	formatted := make([]string, 0, len(values))
+
+	for _, v := range values {
+		formatted = append(formatted, format(v))
+	}
+
+	return joinSlice(formatted, separator, newSliceOptions(opts))
+}
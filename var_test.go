@@ -0,0 +1,169 @@
+package env
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolateRegistry restores the declared variables to their state before the test once it
+// finishes, so tests can declare variables and still be run more than once.
+func isolateRegistry(t testing.TB) {
+	registry.Lock()
+	vars := make(map[string]*Variable, len(registry.vars))
+	bindings := make(map[string]binding, len(registry.bindings))
+	for key, variable := range registry.vars {
+		vars[key] = variable
+		bindings[key] = registry.bindings[key]
+	}
+	registry.Unlock()
+
+	t.Cleanup(func() {
+		registry.Lock()
+		registry.vars = vars
+		registry.bindings = bindings
+		registry.Unlock()
+	})
+}
+
+func TestDurationVar(t *testing.T) {
+	isolateRegistry(t)
+
+	envKey := "KEY_VAR_DURATION"
+	v := DurationVar(envKey, 5*time.Second, "http client timeout")
+
+	if val := v.Get(); val != 5*time.Second {
+		t.Errorf("expected default value %s got %s", 5*time.Second, val)
+	}
+
+	t.Setenv(envKey, "1m")
+
+	if val := v.Get(); val != time.Minute {
+		t.Errorf("expected env var %s value %s got %s", envKey, time.Minute, val)
+	}
+
+	if v.Key() != envKey {
+		t.Errorf("expected key %s got %s", envKey, v.Key())
+	}
+}
+
+func TestSliceVar(t *testing.T) {
+	isolateRegistry(t)
+
+	envKey := "KEY_VAR_INT_SLICE"
+	v := IntSliceVar(envKey, ",", 16, []int{10, 255}, "")
+
+	if err := equalSlices(v.Get(), []int{10, 255}); err != nil {
+		t.Errorf("expected default value %s", err.Error())
+	}
+
+	t.Setenv(envKey, "a,b")
+
+	if err := equalSlices(v.Get(), []int{10, 11}); err != nil {
+		t.Errorf("expected env var value %s", err.Error())
+	}
+}
+
+type customLevel string
+
+func TestVariable(t *testing.T) {
+	isolateRegistry(t)
+
+	defaultURL, err := url.Parse("https://rojbar.com/")
+	if err != nil {
+		t.Errorf("parse url failed %s", err.Error())
+	}
+
+	tests := []struct {
+		variable *Variable
+		key      string
+		typ      string
+		def      string
+	}{
+		{StringVar("KEY_VARIABLE_STRING", customLevel("info"), "").Variable(), "KEY_VARIABLE_STRING", "string", "info"},
+		{BoolVar("KEY_VARIABLE_BOOL", true, "").Variable(), "KEY_VARIABLE_BOOL", "bool", "true"},
+		{IntVar("KEY_VARIABLE_INT8", 2, int8(-10), "").Variable(), "KEY_VARIABLE_INT8", "int8", "-1010"},
+		{UintVar("KEY_VARIABLE_UINT", 16, uint(255), "").Variable(), "KEY_VARIABLE_UINT", "uint", "ff"},
+		{FloatVar("KEY_VARIABLE_FLOAT32", float32(0.1), "").Variable(), "KEY_VARIABLE_FLOAT32", "float32", "0.1"},
+		{URLVar("KEY_VARIABLE_URL", *defaultURL, "").Variable(), "KEY_VARIABLE_URL", "url", "https://rojbar.com/"},
+		{DurationSliceVar("KEY_VARIABLE_DURATION_SLICE", ";", []time.Duration{time.Second, time.Hour}, "").Variable(), "KEY_VARIABLE_DURATION_SLICE", "[]duration", "1s;1h0m0s"},
+	}
+
+	for _, test := range tests {
+		if test.variable.Key != test.key {
+			t.Errorf("expected key %s got %s", test.key, test.variable.Key)
+		}
+
+		if test.variable.Type != test.typ {
+			t.Errorf("expected %s type %s got %s", test.key, test.typ, test.variable.Type)
+		}
+
+		if test.variable.Default != test.def {
+			t.Errorf("expected %s default %s got %s", test.key, test.def, test.variable.Default)
+		}
+
+		if LookupVariable(test.key) != test.variable {
+			t.Errorf("expected %s to be registered", test.key)
+		}
+	}
+}
+
+func TestVarRedeclared(t *testing.T) {
+	isolateRegistry(t)
+
+	StringVar("KEY_VAR_REDECLARED", "", "")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected redeclaration to panic")
+		}
+	}()
+
+	StringVar("KEY_VAR_REDECLARED", "", "")
+}
+
+func TestVarInvalidBase(t *testing.T) {
+	isolateRegistry(t)
+
+	for name, declare := range map[string]func(){
+		"IntVar":       func() { IntVar("KEY_VAR_INVALID_BASE", 1, 5, "") },
+		"UintVar":      func() { UintVar("KEY_VAR_INVALID_BASE", 40, uint(5), "") },
+		"IntSliceVar":  func() { IntSliceVar[int]("KEY_VAR_INVALID_BASE", ",", -1, nil, "") },
+		"UintSliceVar": func() { UintSliceVar[uint]("KEY_VAR_INVALID_BASE", ",", 37, nil, "") },
+	} {
+		func() {
+			defer func() {
+				if r, _ := recover().(string); !strings.HasPrefix(r, "env: invalid base ") || !strings.HasSuffix(r, " for KEY_VAR_INVALID_BASE") {
+					t.Errorf("expected %s to panic with an invalid base got %q", name, r)
+				}
+			}()
+
+			declare()
+		}()
+	}
+
+	VisitAll(func(v *Variable) {
+		if v.Key == "KEY_VAR_INVALID_BASE" {
+			t.Errorf("expected KEY_VAR_INVALID_BASE not to be declared")
+		}
+	})
+}
+
+func TestVisitAll(t *testing.T) {
+	isolateRegistry(t)
+
+	StringVar("KEY_VISIT_B", "", "")
+	StringVar("KEY_VISIT_A", "", "")
+
+	var keys []string
+	VisitAll(func(v *Variable) {
+		keys = append(keys, v.Key)
+	})
+
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			t.Errorf("expected keys in lexicographical order got %s before %s", keys[i-1], keys[i])
+		}
+	}
+}